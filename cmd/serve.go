@@ -54,7 +54,7 @@ http mode: Runs HTTP server with /mcp/v1/messages endpoint (for remote access)`,
 		ynabClient := ynab.NewClient(cfg.YNABToken)
 
 		// Create MCP server
-		mcpServer, err := server.NewMCPServer(ynabClient)
+		mcpServer, err := server.NewMCPServer(ynabClient, cfg)
 		if err != nil {
 			log.Fatalf("Failed to create MCP server: %v", err)
 		}
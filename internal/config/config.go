@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 
 	"github.com/spf13/viper"
 )
@@ -16,6 +18,58 @@ type Config struct {
 	HTTPHost      string
 	MCPAuthToken  string
 	LogLevel      string
+
+	// CategorizationRules are evaluated in order by the rule-based
+	// categorization tools; the first matching rule wins
+	CategorizationRules []CategorizationRule
+
+	// BaseCurrency is the ISO code consolidated multi-budget reports are
+	// converted to. ExchangeRates gives the value of one unit of each other
+	// currency in the base currency; keys are ISO codes.
+	BaseCurrency  string
+	ExchangeRates map[string]float64
+}
+
+// CategorizationRule matches transactions and describes the changes to apply.
+// All conditions that are set must match. Amounts are signed currency units
+// (outflows are negative).
+type CategorizationRule struct {
+	Name       string   `mapstructure:"name"`
+	PayeeRegex string   `mapstructure:"payee_regex"`
+	MemoRegex  string   `mapstructure:"memo_regex"`
+	MinAmount  *float64 `mapstructure:"min_amount"`
+	MaxAmount  *float64 `mapstructure:"max_amount"`
+	AccountID  string   `mapstructure:"account_id"`
+
+	CategoryID   string `mapstructure:"category_id"`
+	RenamePayee  string `mapstructure:"rename_payee"`
+	FlagColor    string `mapstructure:"flag_color"`
+	MemoTemplate string `mapstructure:"memo_template"`
+}
+
+// Validate checks that the rule has at least one condition and one action
+// and that its regular expressions compile
+func (r CategorizationRule) Validate() error {
+	if r.PayeeRegex == "" && r.MemoRegex == "" && r.MinAmount == nil && r.MaxAmount == nil && r.AccountID == "" {
+		return fmt.Errorf("at least one condition is required (payee_regex, memo_regex, min_amount, max_amount, account_id)")
+	}
+	if r.CategoryID == "" && r.RenamePayee == "" && r.FlagColor == "" && r.MemoTemplate == "" {
+		return fmt.Errorf("at least one action is required (category_id, rename_payee, flag_color, memo_template)")
+	}
+	if r.PayeeRegex != "" {
+		if _, err := regexp.Compile(r.PayeeRegex); err != nil {
+			return fmt.Errorf("invalid payee_regex: %w", err)
+		}
+	}
+	if r.MemoRegex != "" {
+		if _, err := regexp.Compile(r.MemoRegex); err != nil {
+			return fmt.Errorf("invalid memo_regex: %w", err)
+		}
+	}
+	if r.MinAmount != nil && r.MaxAmount != nil && *r.MinAmount > *r.MaxAmount {
+		return fmt.Errorf("min_amount must not be greater than max_amount")
+	}
+	return nil
 }
 
 // Load reads configuration from multiple sources with precedence:
@@ -89,5 +143,33 @@ func Load(configPath string) (*Config, error) {
 		return nil, fmt.Errorf("YNAB access token is required (set YNAB_ACCESS_TOKEN env var or add to config file)")
 	}
 
+	// Load categorization rules (config file only)
+	if err := v.UnmarshalKey("categorization_rules", &cfg.CategorizationRules); err != nil {
+		return nil, fmt.Errorf("failed to parse categorization_rules: %w", err)
+	}
+	for i, rule := range cfg.CategorizationRules {
+		if err := rule.Validate(); err != nil {
+			return nil, fmt.Errorf("categorization rule %d (%s): %w", i+1, rule.Name, err)
+		}
+	}
+
+	// Load exchange rates (config file only). Viper lowercases map keys, so
+	// normalize currency codes to upper case.
+	cfg.BaseCurrency = strings.ToUpper(v.GetString("base_currency"))
+	var rates map[string]float64
+	if err := v.UnmarshalKey("exchange_rates", &rates); err != nil {
+		return nil, fmt.Errorf("failed to parse exchange_rates: %w", err)
+	}
+	cfg.ExchangeRates = make(map[string]float64, len(rates))
+	for code, rate := range rates {
+		if rate <= 0 {
+			return nil, fmt.Errorf("exchange rate for %s must be positive", strings.ToUpper(code))
+		}
+		cfg.ExchangeRates[strings.ToUpper(code)] = rate
+	}
+	if len(cfg.ExchangeRates) > 0 && cfg.BaseCurrency == "" {
+		return nil, fmt.Errorf("base_currency is required when exchange_rates are set")
+	}
+
 	return cfg, nil
 }
@@ -1,13 +1,14 @@
 package server
 
 import (
+	"github.com/jeff-french/ynab-mcp-server/internal/config"
 	"github.com/jeff-french/ynab-mcp-server/internal/tools"
 	"github.com/jeff-french/ynab-mcp-server/internal/ynab"
 	"github.com/mark3labs/mcp-go/server"
 )
 
 // NewMCPServer creates and configures the MCP server with all YNAB tools
-func NewMCPServer(ynabClient *ynab.Client) (*server.MCPServer, error) {
+func NewMCPServer(ynabClient *ynab.Client, cfg *config.Config) (*server.MCPServer, error) {
 	// Create MCP server
 	mcpServer := server.NewMCPServer(
 		"ynab-mcp-server",
@@ -16,7 +17,7 @@ func NewMCPServer(ynabClient *ynab.Client) (*server.MCPServer, error) {
 	)
 
 	// Register all tools with their handlers
-	allTools := tools.GetAllTools(ynabClient)
+	allTools := tools.GetAllTools(ynabClient, cfg)
 	for _, toolDef := range allTools {
 		mcpServer.AddTool(toolDef.Tool, toolDef.Handler)
 	}
@@ -2,6 +2,7 @@ package tools
 
 import (
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/jeff-french/ynab-mcp-server/internal/ynab"
@@ -14,6 +15,146 @@ func isTransfer(tx ynab.Transaction) bool {
 	return tx.TransferAccountID != ""
 }
 
+// isAnalyzable checks if a transaction should count toward spending analysis
+// (excludes transfers and deleted transactions)
+func isAnalyzable(tx ynab.Transaction) bool {
+	return !isTransfer(tx) && !tx.Deleted
+}
+
+// Transfer handling modes for spending aggregation
+const (
+	// transferHandlingExclude drops every transfer
+	transferHandlingExclude = "exclude"
+	// transferHandlingOffBudget counts the on-budget side of transfers to or
+	// from off-budget accounts, which YNAB categorizes like any other outflow
+	transferHandlingOffBudget = "off_budget"
+	// transferHandlingAll counts every transfer, including both sides of
+	// transfers between on-budget accounts
+	transferHandlingAll = "all"
+)
+
+// transferHandlingProperty is the input schema for the transfer_handling option
+func transferHandlingProperty() map[string]interface{} {
+	return map[string]interface{}{
+		"type":        "string",
+		"description": "Optional: how to count transfers. 'off_budget' (default) counts transfers between on-budget and off-budget accounts as spending or income, 'exclude' drops all transfers, 'all' counts every transfer",
+		"enum":        []string{transferHandlingOffBudget, transferHandlingExclude, transferHandlingAll},
+	}
+}
+
+// parseTransferHandling reads the transfer_handling argument
+func parseTransferHandling(args map[string]interface{}) (string, error) {
+	mode, ok := args["transfer_handling"].(string)
+	if !ok || mode == "" {
+		return transferHandlingOffBudget, nil
+	}
+	switch mode {
+	case transferHandlingOffBudget, transferHandlingExclude, transferHandlingAll:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("transfer_handling must be one of: %s, %s, %s", transferHandlingOffBudget, transferHandlingExclude, transferHandlingAll)
+	}
+}
+
+// onBudgetAccounts maps account IDs to whether they are on budget. Only the
+// off_budget transfer mode needs it, so other modes return nil without a request.
+func onBudgetAccounts(client *ynab.Client, budgetID, mode string) (map[string]bool, error) {
+	if mode != transferHandlingOffBudget {
+		return nil, nil
+	}
+	accounts, err := client.ListAccounts(budgetID)
+	if err != nil {
+		return nil, err
+	}
+	onBudget := make(map[string]bool, len(accounts))
+	for _, account := range accounts {
+		onBudget[account.ID] = account.OnBudget
+	}
+	return onBudget, nil
+}
+
+// flattenSplit expands a split transaction into one transaction per
+// subtransaction so each part lands in its own category and payee
+func flattenSplit(tx ynab.Transaction) []ynab.Transaction {
+	if len(tx.Subtransactions) == 0 {
+		return []ynab.Transaction{tx}
+	}
+
+	parts := make([]ynab.Transaction, 0, len(tx.Subtransactions))
+	for _, sub := range tx.Subtransactions {
+		if sub.Deleted {
+			continue
+		}
+		part := tx
+		part.ID = sub.ID
+		part.Amount = sub.Amount
+		part.CategoryID = sub.CategoryID
+		part.CategoryName = sub.CategoryName
+		part.TransferAccountID = sub.TransferAccountID
+		part.TransferTransactionID = sub.TransferTransactionID
+		part.Subtransactions = nil
+		if sub.PayeeID != "" {
+			part.PayeeID = sub.PayeeID
+			part.PayeeName = sub.PayeeName
+		}
+		if sub.Memo != "" {
+			part.Memo = sub.Memo
+		}
+		parts = append(parts, part)
+	}
+	return parts
+}
+
+// countsTransfer reports whether a transfer counts toward spending under mode
+func countsTransfer(tx ynab.Transaction, onBudget map[string]bool, mode string) bool {
+	switch mode {
+	case transferHandlingAll:
+		return true
+	case transferHandlingOffBudget:
+		// Only the on-budget side of a transfer that leaves or enters the budget
+		return onBudget[tx.AccountID] && !onBudget[tx.TransferAccountID]
+	default:
+		return false
+	}
+}
+
+// prepareForAggregation flattens split transactions and drops deleted
+// transactions and the transfers that don't count under mode. The
+// aggregateBy* functions expect their input to be prepared this way.
+func prepareForAggregation(transactions []ynab.Transaction, onBudget map[string]bool, mode string) []ynab.Transaction {
+	prepared := make([]ynab.Transaction, 0, len(transactions))
+	for _, tx := range transactions {
+		if tx.Deleted {
+			continue
+		}
+		for _, part := range flattenSplit(tx) {
+			if isTransfer(part) && !countsTransfer(part, onBudget, mode) {
+				continue
+			}
+			prepared = append(prepared, part)
+		}
+	}
+	return prepared
+}
+
+// liabilityAccountTypes are YNAB account types that hold debt
+var liabilityAccountTypes = map[string]bool{
+	"creditCard":     true,
+	"lineOfCredit":   true,
+	"mortgage":       true,
+	"autoLoan":       true,
+	"studentLoan":    true,
+	"personalLoan":   true,
+	"medicalDebt":    true,
+	"otherDebt":      true,
+	"otherLiability": true,
+}
+
+// isLiabilityAccount checks if an account type represents debt rather than an asset
+func isLiabilityAccount(accountType string) bool {
+	return liabilityAccountTypes[accountType]
+}
+
 // parseDate validates and parses a date string in YYYY-MM-DD format
 func parseDate(dateStr string) (time.Time, error) {
 	if dateStr == "" {
@@ -26,7 +167,7 @@ func parseDate(dateStr string) (time.Time, error) {
 	return t, nil
 }
 
-// validateDateRange checks that dates are valid and range is reasonable
+// validateDateRange checks that dates are valid and in order
 func validateDateRange(sinceDate, untilDate string) error {
 	since, err := parseDate(sinceDate)
 	if err != nil {
@@ -42,12 +183,6 @@ func validateDateRange(sinceDate, untilDate string) error {
 		return fmt.Errorf("until_date must be after since_date")
 	}
 
-	// Check if range is too large (> 2 years)
-	duration := until.Sub(since)
-	if duration > 730*24*time.Hour { // ~2 years
-		return fmt.Errorf("date range too large (max 2 years), consider using a smaller range for better performance")
-	}
-
 	return nil
 }
 
@@ -78,9 +213,6 @@ func getLastNMonths(n int) []string {
 	if n < 1 {
 		n = 1
 	}
-	if n > 24 {
-		n = 24
-	}
 
 	months := make([]string, n)
 	now := time.Now()
@@ -93,6 +225,27 @@ func getLastNMonths(n int) []string {
 	return months
 }
 
+// getMonthRange returns every month from since to until inclusive, both in YYYY-MM format
+func getMonthRange(since, until string) ([]string, error) {
+	start, err := parseMonth(since)
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseMonth(until)
+	if err != nil {
+		return nil, err
+	}
+	if start.After(end) {
+		return nil, fmt.Errorf("start month must be before end month")
+	}
+
+	months := make([]string, 0)
+	for m := start; !m.After(end); m = m.AddDate(0, 1, 0) {
+		months = append(months, getMonthString(m))
+	}
+	return months, nil
+}
+
 // categorySummary holds aggregated data for a category
 type categorySummary struct {
 	CategoryID        string  `json:"category_id"`
@@ -137,54 +290,103 @@ type accountBalance struct {
 
 // aggregateByCategory groups transactions by category and sums amounts
 func aggregateByCategory(transactions []ynab.Transaction) map[string]*categorySummary {
+	return categorySummaries(aggregateTransactions(transactions, []groupingKey{categoryKey}))
+}
+
+// categorySummaries converts groups keyed by categoryKey into category summaries
+func categorySummaries(groups map[string]*aggregateGroup) map[string]*categorySummary {
 	summaries := make(map[string]*categorySummary)
 
-	for _, tx := range transactions {
-		// Skip transfers
-		if isTransfer(tx) {
-			continue
+	for _, group := range groups {
+		summaries[group.IDs[0]] = &categorySummary{
+			CategoryID:        group.IDs[0],
+			CategoryName:      group.Labels[0],
+			CategoryGroupName: "", // Filled in by applyCategoryGroupNames when category data is available
+			TotalOutflow:      group.TotalOutflow,
+			TotalInflow:       group.TotalInflow,
+			Net:               group.Net,
+			TransactionCount:  group.TransactionCount,
 		}
+	}
 
-		// Skip deleted transactions
-		if tx.Deleted {
-			continue
-		}
+	return summaries
+}
 
-		// Get or create category summary
-		categoryID := tx.CategoryID
-		if categoryID == "" {
-			categoryID = "uncategorized"
+// categoryGroupNameIndex maps category IDs to the name of their category group
+func categoryGroupNameIndex(categoryGroups []ynab.CategoryGroup) map[string]string {
+	names := make(map[string]string)
+	for _, group := range categoryGroups {
+		for _, category := range group.Categories {
+			names[category.ID] = group.Name
 		}
+	}
+	return names
+}
 
-		summary, exists := summaries[categoryID]
+// applyCategoryGroupNames fills in CategoryGroupName on category summaries
+func applyCategoryGroupNames(summaries map[string]*categorySummary, categoryGroups []ynab.CategoryGroup) {
+	groupNames := categoryGroupNameIndex(categoryGroups)
+	for categoryID, summary := range summaries {
+		summary.CategoryGroupName = groupNames[categoryID]
+	}
+}
+
+// groupSummary holds aggregated data for a category group and its categories
+type groupSummary struct {
+	CategoryGroupName string            `json:"category_group_name"`
+	TotalOutflow      float64           `json:"total_outflow"`
+	TotalInflow       float64           `json:"total_inflow"`
+	Net               float64           `json:"net"`
+	TransactionCount  int               `json:"transaction_count"`
+	PercentOfOutflow  float64           `json:"percent_of_outflow"`
+	Categories        []categorySummary `json:"categories"`
+}
+
+// rollupByGroup nests category summaries under their category groups, sorted
+// by outflow, with each group's share of totalOutflow
+func rollupByGroup(categories []categorySummary, totalOutflow float64) []*groupSummary {
+	byName := make(map[string]*groupSummary)
+	groups := make([]*groupSummary, 0)
+	for _, category := range categories {
+		name := category.CategoryGroupName
+		if name == "" {
+			name = "Uncategorized"
+		}
+		group, exists := byName[name]
 		if !exists {
-			summary = &categorySummary{
-				CategoryID:        categoryID,
-				CategoryName:      tx.CategoryName,
-				CategoryGroupName: "", // Will be filled in if we have category data
-			}
-			if categoryID == "uncategorized" {
-				summary.CategoryName = "Uncategorized"
-			}
-			summaries[categoryID] = summary
+			group = &groupSummary{CategoryGroupName: name, Categories: []categorySummary{}}
+			byName[name] = group
+			groups = append(groups, group)
 		}
+		group.TotalOutflow += category.TotalOutflow
+		group.TotalInflow += category.TotalInflow
+		group.Net += category.Net
+		group.TransactionCount += category.TransactionCount
+		group.Categories = append(group.Categories, category)
+	}
 
-		// Aggregate amounts
-		amount := ynab.MilliunitsToFloat(tx.Amount)
-		if amount < 0 {
-			summary.TotalOutflow += -amount // Store as positive
-		} else {
-			summary.TotalInflow += amount
+	for _, group := range groups {
+		if totalOutflow > 0 {
+			group.PercentOfOutflow = roundTo(group.TotalOutflow/totalOutflow*100, 1)
 		}
-		summary.Net += amount
-		summary.TransactionCount++
+		sort.Slice(group.Categories, func(i, j int) bool {
+			return group.Categories[i].TotalOutflow > group.Categories[j].TotalOutflow
+		})
 	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].TotalOutflow > groups[j].TotalOutflow
+	})
 
-	return summaries
+	return groups
 }
 
 // aggregateByMonth groups transactions by month and sums amounts
 func aggregateByMonth(transactions []ynab.Transaction, months []string) map[string]*monthSummary {
+	return monthSummaries(aggregateTransactions(transactions, []groupingKey{monthKey}), months)
+}
+
+// monthSummaries converts groups keyed by monthKey into a summary for every month in months
+func monthSummaries(groups map[string]*aggregateGroup, months []string) map[string]*monthSummary {
 	summaries := make(map[string]*monthSummary)
 
 	// Initialize all months with zero values
@@ -194,40 +396,16 @@ func aggregateByMonth(transactions []ynab.Transaction, months []string) map[stri
 		}
 	}
 
-	// Aggregate transactions
-	for _, tx := range transactions {
-		// Skip transfers
-		if isTransfer(tx) {
-			continue
-		}
-
-		// Skip deleted transactions
-		if tx.Deleted {
-			continue
-		}
-
-		// Get month from transaction date
-		txDate, err := parseDate(tx.Date)
-		if err != nil {
-			continue // Skip invalid dates
-		}
-		month := getMonthString(txDate)
-
-		// Only include if in our month list
-		summary, exists := summaries[month]
+	// Only months in our month list are included
+	for _, group := range groups {
+		summary, exists := summaries[group.IDs[0]]
 		if !exists {
 			continue
 		}
-
-		// Aggregate amounts
-		amount := ynab.MilliunitsToFloat(tx.Amount)
-		if amount < 0 {
-			summary.TotalOutflow += -amount // Store as positive
-		} else {
-			summary.TotalInflow += amount
-		}
-		summary.Net += amount
-		summary.TransactionCount++
+		summary.TotalOutflow = group.TotalOutflow
+		summary.TotalInflow = group.TotalInflow
+		summary.Net = group.Net
+		summary.TransactionCount = group.TransactionCount
 	}
 
 	return summaries
@@ -235,46 +413,22 @@ func aggregateByMonth(transactions []ynab.Transaction, months []string) map[stri
 
 // aggregateByPayee groups transactions by payee and sums amounts
 func aggregateByPayee(transactions []ynab.Transaction) map[string]*payeeSummary {
-	summaries := make(map[string]*payeeSummary)
-
-	for _, tx := range transactions {
-		// Skip transfers
-		if isTransfer(tx) {
-			continue
-		}
-
-		// Skip deleted transactions
-		if tx.Deleted {
-			continue
-		}
-
-		// Get or create payee summary
-		payeeID := tx.PayeeID
-		if payeeID == "" {
-			payeeID = "no-payee"
-		}
+	return payeeSummaries(aggregateTransactions(transactions, []groupingKey{payeeKey}))
+}
 
-		summary, exists := summaries[payeeID]
-		if !exists {
-			summary = &payeeSummary{
-				PayeeID:   payeeID,
-				PayeeName: tx.PayeeName,
-			}
-			if payeeID == "no-payee" {
-				summary.PayeeName = "No Payee"
-			}
-			summaries[payeeID] = summary
-		}
+// payeeSummaries converts groups keyed by payeeKey into payee summaries
+func payeeSummaries(groups map[string]*aggregateGroup) map[string]*payeeSummary {
+	summaries := make(map[string]*payeeSummary)
 
-		// Aggregate amounts
-		amount := ynab.MilliunitsToFloat(tx.Amount)
-		if amount < 0 {
-			summary.TotalOutflow += -amount // Store as positive
-		} else {
-			summary.TotalInflow += amount
+	for _, group := range groups {
+		summaries[group.IDs[0]] = &payeeSummary{
+			PayeeID:          group.IDs[0],
+			PayeeName:        group.Labels[0],
+			TotalOutflow:     group.TotalOutflow,
+			TotalInflow:      group.TotalInflow,
+			Net:              group.Net,
+			TransactionCount: group.TransactionCount,
 		}
-		summary.Net += amount
-		summary.TransactionCount++
 	}
 
 	return summaries
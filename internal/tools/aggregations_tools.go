@@ -34,6 +34,12 @@ func NewGetSpendingByCategoryTool(client *ynab.Client) ToolDefinition {
 					"type":        "string",
 					"description": "Optional: filter to specific account ID",
 				},
+				"group_by": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional: 'category' for a flat list (default) or 'group' to nest categories under their category groups with each group's share of total outflow",
+					"enum":        []string{"category", "group"},
+				},
+				"transfer_handling": transferHandlingProperty(),
 			},
 			Required: []string{"budget_id", "since_date", "until_date"},
 		},
@@ -50,6 +56,19 @@ func NewGetSpendingByCategoryTool(client *ynab.Client) ToolDefinition {
 			return mcp.NewToolResultError("budget_id is required"), nil
 		}
 
+		transferHandling, err := parseTransferHandling(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		groupBy := "category"
+		if groupByArg, ok := args["group_by"].(string); ok && groupByArg != "" {
+			if groupByArg != "category" && groupByArg != "group" {
+				return mcp.NewToolResultError("group_by must be 'category' or 'group'"), nil
+			}
+			groupBy = groupByArg
+		}
+
 		sinceDate, ok := args["since_date"].(string)
 		if !ok || sinceDate == "" {
 			return mcp.NewToolResultError("since_date is required (YYYY-MM-DD format)"), nil
@@ -65,39 +84,31 @@ func NewGetSpendingByCategoryTool(client *ynab.Client) ToolDefinition {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		// Fetch transactions for date range
-		query := &ynab.TransactionQuery{
+		filter := &transactionFilter{
 			SinceDate: sinceDate,
+			UntilDate: untilDate,
 		}
-
-		var transactions []ynab.Transaction
-		var err error
-
 		if accountID, ok := args["account_id"].(string); ok && accountID != "" {
-			transactions, err = client.ListAccountTransactions(budgetID, accountID, query)
-		} else {
-			transactions, err = client.ListTransactions(budgetID, query)
+			filter.AccountID = accountID
 		}
 
+		onBudget, err := onBudgetAccounts(client, budgetID, transferHandling)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch transactions: %v", err)), nil
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch accounts: %v", err)), nil
 		}
 
-		// Filter transactions to until_date (YNAB's since_date doesn't have until)
-		untilTime, _ := parseDate(untilDate)
-		filteredTxs := make([]ynab.Transaction, 0)
-		for _, tx := range transactions {
-			txDate, err := parseDate(tx.Date)
-			if err != nil {
-				continue
-			}
-			if !txDate.After(untilTime) {
-				filteredTxs = append(filteredTxs, tx)
-			}
+		// Aggregate by category as transactions stream in
+		agg := newAggregator([]groupingKey{categoryKey})
+		if _, err := streamAggregationLines(client, budgetID, filter, onBudget, transferHandling, newProgressReporter(ctx, request), agg.add); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
 		}
+		summaries := categorySummaries(agg.groups)
 
-		// Aggregate by category
-		summaries := aggregateByCategory(filteredTxs)
+		categoryGroups, err := client.ListCategories(budgetID)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch categories: %v", err)), nil
+		}
+		applyCategoryGroupNames(summaries, categoryGroups)
 
 		// Convert to sorted slice
 		categories := make([]categorySummary, 0, len(summaries))
@@ -117,7 +128,6 @@ func NewGetSpendingByCategoryTool(client *ynab.Client) ToolDefinition {
 
 		// Build result
 		result := map[string]interface{}{
-			"categories":    categories,
 			"total_outflow": totalOutflow,
 			"total_inflow":  totalInflow,
 			"date_range": map[string]string{
@@ -125,6 +135,11 @@ func NewGetSpendingByCategoryTool(client *ynab.Client) ToolDefinition {
 				"until": untilDate,
 			},
 		}
+		if groupBy == "group" {
+			result["category_groups"] = rollupByGroup(categories, totalOutflow)
+		} else {
+			result["categories"] = categories
+		}
 
 		jsonResult, err := json.MarshalIndent(result, "", "  ")
 		if err != nil {
@@ -155,14 +170,14 @@ func NewGetSpendingByMonthTool(client *ynab.Client) ToolDefinition {
 				},
 				"num_months": map[string]interface{}{
 					"type":        "number",
-					"description": "Number of months including current (1-24)",
+					"description": "Number of months including current (at least 1)",
 					"minimum":     1,
-					"maximum":     24,
 				},
 				"account_id": map[string]interface{}{
 					"type":        "string",
 					"description": "Optional: filter to specific account ID",
 				},
+				"transfer_handling": transferHandlingProperty(),
 			},
 			Required: []string{"budget_id", "num_months"},
 		},
@@ -179,13 +194,18 @@ func NewGetSpendingByMonthTool(client *ynab.Client) ToolDefinition {
 			return mcp.NewToolResultError("budget_id is required"), nil
 		}
 
+		transferHandling, err := parseTransferHandling(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
 		numMonthsFloat, ok := args["num_months"].(float64)
 		if !ok {
-			return mcp.NewToolResultError("num_months is required (1-24)"), nil
+			return mcp.NewToolResultError("num_months is required"), nil
 		}
 		numMonths := int(numMonthsFloat)
-		if numMonths < 1 || numMonths > 24 {
-			return mcp.NewToolResultError("num_months must be between 1 and 24"), nil
+		if numMonths < 1 {
+			return mcp.NewToolResultError("num_months must be at least 1"), nil
 		}
 
 		// Get category name if filtering by category
@@ -208,39 +228,26 @@ func NewGetSpendingByMonthTool(client *ynab.Client) ToolDefinition {
 		}
 
 		// Get since date from oldest month
-		sinceDate := months[0] + "-01"
-
-		// Fetch transactions
-		query := &ynab.TransactionQuery{
-			SinceDate: sinceDate,
+		filter := &transactionFilter{
+			SinceDate:  months[0] + "-01",
+			CategoryID: categoryID,
 		}
-
-		var transactions []ynab.Transaction
-		var err error
-
 		if accountID, ok := args["account_id"].(string); ok && accountID != "" {
-			transactions, err = client.ListAccountTransactions(budgetID, accountID, query)
-		} else {
-			transactions, err = client.ListTransactions(budgetID, query)
+			filter.AccountID = accountID
 		}
 
+		onBudget, err := onBudgetAccounts(client, budgetID, transferHandling)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch transactions: %v", err)), nil
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch accounts: %v", err)), nil
 		}
 
-		// Filter by category if specified
-		if categoryID != "" {
-			filtered := make([]ynab.Transaction, 0)
-			for _, tx := range transactions {
-				if tx.CategoryID == categoryID {
-					filtered = append(filtered, tx)
-				}
-			}
-			transactions = filtered
+		// Aggregate by month as transactions stream in; the category filter
+		// applies per split line
+		agg := newAggregator([]groupingKey{monthKey})
+		if _, err := streamAggregationLines(client, budgetID, filter, onBudget, transferHandling, newProgressReporter(ctx, request), agg.add); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
 		}
-
-		// Aggregate by month
-		summaries := aggregateByMonth(transactions, months)
+		summaries := monthSummaries(agg.groups, months)
 
 		// Convert to sorted slice (chronological order)
 		monthData := make([]monthSummary, len(months))
@@ -416,6 +423,7 @@ func NewGetPayeeSummaryTool(client *ynab.Client) ToolDefinition {
 					"description": "Optional: return top N payees (default 20)",
 					"default":     20,
 				},
+				"transfer_handling": transferHandlingProperty(),
 			},
 			Required: []string{"budget_id", "since_date", "until_date"},
 		},
@@ -447,6 +455,11 @@ func NewGetPayeeSummaryTool(client *ynab.Client) ToolDefinition {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
+		transferHandling, err := parseTransferHandling(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
 		topN := 20
 		if topNFloat, ok := args["top_n"].(float64); ok {
 			topN = int(topNFloat)
@@ -455,31 +468,22 @@ func NewGetPayeeSummaryTool(client *ynab.Client) ToolDefinition {
 			}
 		}
 
-		// Fetch transactions
-		query := &ynab.TransactionQuery{
+		filter := &transactionFilter{
 			SinceDate: sinceDate,
+			UntilDate: untilDate,
 		}
 
-		transactions, err := client.ListTransactions(budgetID, query)
+		onBudget, err := onBudgetAccounts(client, budgetID, transferHandling)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch transactions: %v", err)), nil
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch accounts: %v", err)), nil
 		}
 
-		// Filter to until_date
-		untilTime, _ := parseDate(untilDate)
-		filteredTxs := make([]ynab.Transaction, 0)
-		for _, tx := range transactions {
-			txDate, err := parseDate(tx.Date)
-			if err != nil {
-				continue
-			}
-			if !txDate.After(untilTime) {
-				filteredTxs = append(filteredTxs, tx)
-			}
+		// Aggregate by payee as transactions stream in
+		agg := newAggregator([]groupingKey{payeeKey})
+		if _, err := streamAggregationLines(client, budgetID, filter, onBudget, transferHandling, newProgressReporter(ctx, request), agg.add); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
 		}
-
-		// Aggregate by payee
-		summaries := aggregateByPayee(filteredTxs)
+		summaries := payeeSummaries(agg.groups)
 
 		// Convert to sorted slice
 		payees := make([]payeeSummary, 0, len(summaries))
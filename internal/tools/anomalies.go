@@ -0,0 +1,367 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/jeff-french/ynab-mcp-server/internal/ynab"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	defaultAnomalyBaselineMonths = 6
+	defaultAnomalyThreshold      = 3.5
+	defaultAnomalyMinAmount      = 25.0
+
+	// Without spread in the baseline, fall back to a ratio against the median
+	anomalyRatioFallback = 2.0
+
+	// Payees need a few past charges before a single one can look unusual
+	minPayeeHistoryForLargeCharge = 3
+)
+
+// spendingAnomaly is a category, payee or transaction that stands out from its baseline
+type spendingAnomaly struct {
+	Kind           string   `json:"kind"`
+	ID             string   `json:"id,omitempty"`
+	Name           string   `json:"name"`
+	Amount         float64  `json:"amount"`
+	BaselineMedian float64  `json:"baseline_median"`
+	BaselineMean   *float64 `json:"baseline_mean,omitempty"`
+	Ratio          *float64 `json:"ratio,omitempty"`
+	Score          *float64 `json:"score,omitempty"`
+	Date           string   `json:"date,omitempty"`
+	ParentID       string   `json:"parent_transaction_id,omitempty"`
+	Explanation    string   `json:"explanation"`
+}
+
+// scoreAgainstBaseline decides whether value is an outlier relative to
+// baseline and returns the ratio to the baseline median and robust z-score
+func scoreAgainstBaseline(value float64, baseline []float64, threshold float64) (ratio, score *float64, anomalous bool) {
+	center := median(baseline)
+	if center > 0 {
+		r := roundTo(value/center, 1)
+		ratio = &r
+	}
+
+	if z, ok := robustZScore(value, baseline); ok {
+		z = roundTo(z, 1)
+		score = &z
+		return ratio, score, z >= threshold
+	}
+	return ratio, score, ratio != nil && *ratio >= anomalyRatioFallback
+}
+
+// newSpending reports a category's or payee's outflow for the month when it
+// had no spending at all in the baseline months. There is nothing to score
+// it against, so it is listed apart from the anomalies.
+func newSpending(kind, id, name string, outflow float64, history []float64, baselineMonths int, minAmount float64) (spendingAnomaly, bool) {
+	if outflow < minAmount || mean(history) != 0 {
+		return spendingAnomaly{}, false
+	}
+	return spendingAnomaly{
+		Kind:   kind,
+		ID:     id,
+		Name:   name,
+		Amount: roundTo(outflow, 2),
+		Explanation: fmt.Sprintf("%s %s with no spending in the previous %d months", name,
+			ynab.FormatCurrency(ynab.FloatToMilliunits(outflow)), baselineMonths),
+	}, true
+}
+
+// periodAnomaly checks one category's or payee's outflow for the month
+// against its baseline months. Spending without a baseline is left to newSpending.
+func periodAnomaly(kind, id, name string, outflow float64, history []float64, baselineMonths int, threshold, minAmount float64) (spendingAnomaly, bool) {
+	if outflow < minAmount || mean(history) == 0 {
+		return spendingAnomaly{}, false
+	}
+
+	anomaly := spendingAnomaly{
+		Kind:   kind,
+		ID:     id,
+		Name:   name,
+		Amount: roundTo(outflow, 2),
+	}
+	amount := ynab.FormatCurrency(ynab.FloatToMilliunits(outflow))
+
+	center := median(history)
+	if center == 0 {
+		return sporadicAnomaly(anomaly, outflow, history, baselineMonths)
+	}
+
+	ratio, score, anomalous := scoreAgainstBaseline(outflow, history, threshold)
+	if !anomalous {
+		return spendingAnomaly{}, false
+	}
+	anomaly.BaselineMedian = roundTo(center, 2)
+	anomaly.Ratio = ratio
+	anomaly.Score = score
+	anomaly.Explanation = fmt.Sprintf("%s %.1fx the %d-month median (%s vs %s)", name, *ratio, baselineMonths,
+		amount, ynab.FormatCurrency(ynab.FloatToMilliunits(center)))
+	return anomaly, true
+}
+
+// sporadicAnomaly checks spending that happened in at most half of the
+// baseline months. Its median and spread are zero, so the month is compared
+// with the baseline's mean instead.
+func sporadicAnomaly(anomaly spendingAnomaly, outflow float64, history []float64, baselineMonths int) (spendingAnomaly, bool) {
+	average := mean(history)
+	ratio := roundTo(outflow/average, 1)
+	if ratio < anomalyRatioFallback {
+		return spendingAnomaly{}, false
+	}
+
+	active := 0
+	for _, value := range history {
+		if value > 0 {
+			active++
+		}
+	}
+	baselineMean := roundTo(average, 2)
+	anomaly.BaselineMean = &baselineMean
+	anomaly.Ratio = &ratio
+	anomaly.Explanation = fmt.Sprintf("%s %.1fx the %d-month average (%s vs %s; spent in only %d of those months)", anomaly.Name, ratio,
+		baselineMonths, ynab.FormatCurrency(ynab.FloatToMilliunits(outflow)), ynab.FormatCurrency(ynab.FloatToMilliunits(average)), active)
+	return anomaly, true
+}
+
+// NewDetectSpendingAnomaliesTool creates the detect_spending_anomalies tool
+func NewDetectSpendingAnomaliesTool(client *ynab.Client) ToolDefinition {
+	tool := mcp.Tool{
+		Name:        "detect_spending_anomalies",
+		Description: "Flag unusual spending in a month. Builds per-category and per-payee baselines from the previous N months and reports outliers using a median-absolute-deviation score (spending seen in only some baseline months is compared with its average), plus single transactions that are unusually large for their payee (split lines include parent_transaction_id). Categories and payees with no spending in the baseline are listed separately as new_spending. Each anomaly includes a plain explanation such as \"Dining 2.4x the 6-month median\".",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"budget_id": map[string]interface{}{
+					"type":        "string",
+					"description": "The ID of the budget",
+				},
+				"month": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional: month to check in YYYY-MM format (default: current month, month to date)",
+				},
+				"baseline_months": map[string]interface{}{
+					"type":        "number",
+					"description": fmt.Sprintf("Optional: number of preceding months to use as the baseline (at least 2, default %d)", defaultAnomalyBaselineMonths),
+					"minimum":     2,
+				},
+				"threshold": map[string]interface{}{
+					"type":        "number",
+					"description": fmt.Sprintf("Optional: robust z-score needed to flag an outlier (default %.1f)", defaultAnomalyThreshold),
+				},
+				"min_amount": map[string]interface{}{
+					"type":        "number",
+					"description": fmt.Sprintf("Optional: ignore anomalies smaller than this amount (default %.0f)", defaultAnomalyMinAmount),
+				},
+			},
+			Required: []string{"budget_id"},
+		},
+	}
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		budgetID, ok := args["budget_id"].(string)
+		if !ok || budgetID == "" {
+			return mcp.NewToolResultError("budget_id is required"), nil
+		}
+
+		month := getCurrentMonth()
+		if monthArg, ok := args["month"].(string); ok && monthArg != "" {
+			month = monthArg
+		}
+		target, err := parseMonth(month)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid month format: %v", err)), nil
+		}
+
+		baselineMonths := defaultAnomalyBaselineMonths
+		if monthsFloat, ok := args["baseline_months"].(float64); ok {
+			baselineMonths = int(monthsFloat)
+			if baselineMonths < 2 {
+				return mcp.NewToolResultError("baseline_months must be at least 2"), nil
+			}
+		}
+
+		threshold := defaultAnomalyThreshold
+		if thresholdFloat, ok := args["threshold"].(float64); ok && thresholdFloat > 0 {
+			threshold = thresholdFloat
+		}
+
+		minAmount := defaultAnomalyMinAmount
+		if minFloat, ok := args["min_amount"].(float64); ok && minFloat >= 0 {
+			minAmount = minFloat
+		}
+
+		baseline, err := getMonthRange(
+			getMonthString(target.AddDate(0, -baselineMonths, 0)),
+			getMonthString(target.AddDate(0, -1, 0)),
+		)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid baseline range: %v", err)), nil
+		}
+
+		onBudget, err := onBudgetAccounts(client, budgetID, transferHandlingOffBudget)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch accounts: %v", err)), nil
+		}
+
+		baselineIndex := make(map[string]int, len(baseline))
+		for i, m := range baseline {
+			baselineIndex[m] = i
+		}
+
+		// Baseline months are summed as they stream in; only the checked
+		// month's lines are kept. Split lines are reported with their parent,
+		// which is what get_transaction can look up.
+		byMonthCategory := newAggregator([]groupingKey{monthKey, categoryKey})
+		byMonthPayee := newAggregator([]groupingKey{monthKey, payeeKey})
+		byMonth := newAggregator([]groupingKey{monthKey})
+		payeeCharges := make(map[string][]float64)
+		current := make([]ynab.Transaction, 0)
+		parents := make(map[string]string)
+		filter := &transactionFilter{
+			SinceDate: baseline[0] + "-01",
+			UntilDate: target.AddDate(0, 1, -1).Format("2006-01-02"),
+		}
+		_, err = streamAggregationTransactions(client, budgetID, filter, onBudget, transferHandlingOffBudget, newProgressReporter(ctx, request), func(tx ynab.Transaction, lines []ynab.Transaction) {
+			for _, line := range lines {
+				byMonthCategory.add(line)
+				byMonthPayee.add(line)
+				byMonth.add(line)
+
+				lineMonth, _ := monthKey(line)
+				if lineMonth == month {
+					current = append(current, line)
+					if line.ID != tx.ID {
+						parents[line.ID] = tx.ID
+					}
+					continue
+				}
+				// Single transactions are compared with the payee's past charges
+				if _, inBaseline := baselineIndex[lineMonth]; inBaseline && isAnalyzable(line) && line.Amount < 0 && line.PayeeID != "" {
+					payeeCharges[line.PayeeID] = append(payeeCharges[line.PayeeID], -ynab.MilliunitsToFloat(line.Amount))
+				}
+			}
+		})
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		anomalies := make([]spendingAnomaly, 0)
+		newItems := make([]spendingAnomaly, 0)
+
+		// Outflow per category and payee for each baseline month (zero when absent)
+		baselineOutflows := func(groups map[string]*aggregateGroup) map[string][]float64 {
+			outflows := make(map[string][]float64)
+			for _, group := range groups {
+				i, inBaseline := baselineIndex[group.IDs[0]]
+				if !inBaseline {
+					continue
+				}
+				id := group.IDs[1]
+				if outflows[id] == nil {
+					outflows[id] = make([]float64, len(baseline))
+				}
+				outflows[id][i] = group.TotalOutflow
+			}
+			return outflows
+		}
+		baselineByCategory := baselineOutflows(byMonthCategory.groups)
+		baselineByPayee := baselineOutflows(byMonthPayee.groups)
+
+		for id, summary := range aggregateByCategory(current) {
+			if anomaly, found := periodAnomaly("category", id, summary.CategoryName, summary.TotalOutflow, baselineByCategory[id], len(baseline), threshold, minAmount); found {
+				anomalies = append(anomalies, anomaly)
+			}
+			if item, found := newSpending("category", id, summary.CategoryName, summary.TotalOutflow, baselineByCategory[id], len(baseline), minAmount); found {
+				newItems = append(newItems, item)
+			}
+		}
+		for id, summary := range aggregateByPayee(current) {
+			if anomaly, found := periodAnomaly("payee", id, summary.PayeeName, summary.TotalOutflow, baselineByPayee[id], len(baseline), threshold, minAmount); found {
+				anomalies = append(anomalies, anomaly)
+			}
+			if item, found := newSpending("payee", id, summary.PayeeName, summary.TotalOutflow, baselineByPayee[id], len(baseline), minAmount); found {
+				newItems = append(newItems, item)
+			}
+		}
+
+		// Single transactions: compare each outflow with the payee's past charges
+		for _, tx := range current {
+			if !isAnalyzable(tx) || tx.Amount >= 0 || tx.PayeeID == "" {
+				continue
+			}
+			amount := -ynab.MilliunitsToFloat(tx.Amount)
+			history := payeeCharges[tx.PayeeID]
+			if amount < minAmount || len(history) < minPayeeHistoryForLargeCharge {
+				continue
+			}
+			ratio, score, anomalous := scoreAgainstBaseline(amount, history, threshold)
+			if !anomalous || ratio == nil {
+				continue
+			}
+			anomalies = append(anomalies, spendingAnomaly{
+				Kind:           "transaction",
+				ID:             tx.ID,
+				Name:           tx.PayeeName,
+				Amount:         roundTo(amount, 2),
+				BaselineMedian: roundTo(median(history), 2),
+				Ratio:          ratio,
+				Score:          score,
+				Date:           tx.Date,
+				ParentID:       parents[tx.ID],
+				Explanation: fmt.Sprintf("%s charge on %s is %.1fx the usual %s (%d past charges)",
+					tx.PayeeName, tx.Date, *ratio, ynab.FormatCurrency(ynab.FloatToMilliunits(median(history))), len(history)),
+			})
+		}
+
+		sort.SliceStable(anomalies, func(i, j int) bool {
+			return anomalies[i].Amount-anomalies[i].BaselineMedian > anomalies[j].Amount-anomalies[j].BaselineMedian
+		})
+		sort.SliceStable(newItems, func(i, j int) bool {
+			return newItems[i].Amount > newItems[j].Amount
+		})
+
+		// Overall spending for context
+		totals := monthSummaries(byMonth.groups, append(append([]string(nil), baseline...), month))
+		baselineTotals := make([]float64, len(baseline))
+		for i, m := range baseline {
+			baselineTotals[i] = totals[m].TotalOutflow
+		}
+
+		// Build result
+		result := map[string]interface{}{
+			"month":        month,
+			"anomalies":    anomalies,
+			"count":        len(anomalies),
+			"new_spending": newItems,
+			"total_spending": map[string]float64{
+				"this_month":      roundTo(totals[month].TotalOutflow, 2),
+				"baseline_median": roundTo(median(baselineTotals), 2),
+			},
+			"baseline_period": map[string]string{
+				"since_month": baseline[0],
+				"until_month": baseline[len(baseline)-1],
+			},
+		}
+		if month == getCurrentMonth() {
+			result["note"] = "The current month is incomplete; amounts are month to date."
+		}
+
+		jsonResult, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to format result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonResult)), nil
+	}
+
+	return ToolDefinition{Tool: tool, Handler: handler}
+}
@@ -0,0 +1,436 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jeff-french/ynab-mcp-server/internal/ynab"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// bulkChange describes the field changes to apply to every selected transaction
+type bulkChange struct {
+	CategoryID string
+	Approved   *bool
+	FlagColor  string
+	Cleared    string
+}
+
+// isEmpty reports whether the change would not modify anything
+func (c bulkChange) isEmpty() bool {
+	return c.CategoryID == "" && c.Approved == nil && c.FlagColor == "" && c.Cleared == ""
+}
+
+// plannedUpdate pairs a transaction with the update that will be sent for it
+type plannedUpdate struct {
+	Transaction ynab.Transaction
+	Update      ynab.BulkTransactionUpdate
+	Changes     []string // human readable description of each field change
+}
+
+// planBulkChange builds the update for a transaction, skipping fields that
+// already have the requested value. Returns false if nothing would change.
+func planBulkChange(tx ynab.Transaction, change bulkChange, categoryNames map[string]string) (plannedUpdate, bool) {
+	plan := plannedUpdate{
+		Transaction: tx,
+		Update:      ynab.BulkTransactionUpdate{ID: tx.ID},
+	}
+
+	if change.CategoryID != "" && tx.CategoryID != change.CategoryID {
+		plan.Update.CategoryID = change.CategoryID
+		plan.Changes = append(plan.Changes, fmt.Sprintf("category: %s → %s",
+			displayOrNone(tx.CategoryName), displayOrNone(lookupName(categoryNames, change.CategoryID))))
+	}
+	if change.Approved != nil && tx.Approved != *change.Approved {
+		approved := *change.Approved
+		plan.Update.Approved = &approved
+		plan.Changes = append(plan.Changes, fmt.Sprintf("approved: %t → %t", tx.Approved, approved))
+	}
+	if change.FlagColor != "" && tx.FlagColor != change.FlagColor {
+		plan.Update.FlagColor = change.FlagColor
+		plan.Changes = append(plan.Changes, fmt.Sprintf("flag: %s → %s", displayOrNone(tx.FlagColor), change.FlagColor))
+	}
+	if change.Cleared != "" && tx.Cleared != change.Cleared {
+		plan.Update.Cleared = change.Cleared
+		plan.Changes = append(plan.Changes, fmt.Sprintf("cleared: %s → %s", tx.Cleared, change.Cleared))
+	}
+
+	return plan, len(plan.Changes) > 0
+}
+
+// displayOrNone returns value, or "(none)" if it is empty
+func displayOrNone(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
+
+// lookupName returns the name for an ID, falling back to the ID itself
+func lookupName(names map[string]string, id string) string {
+	if name, ok := names[id]; ok {
+		return name
+	}
+	return id
+}
+
+// categoryNameIndex maps category IDs to category names
+func categoryNameIndex(categoryGroups []ynab.CategoryGroup) map[string]string {
+	names := make(map[string]string)
+	for _, group := range categoryGroups {
+		for _, category := range group.Categories {
+			names[category.ID] = category.Name
+		}
+	}
+	return names
+}
+
+// writeUpdatePreview writes a line per planned update describing its changes
+func writeUpdatePreview(result *strings.Builder, plans []plannedUpdate) {
+	for i, plan := range plans {
+		tx := plan.Transaction
+		result.WriteString(fmt.Sprintf("%d. %s - %s %s\n", i+1, tx.Date, tx.PayeeName, ynab.FormatCurrency(tx.Amount)))
+		result.WriteString(fmt.Sprintf("   ID: %s\n", tx.ID))
+		for _, change := range plan.Changes {
+			result.WriteString(fmt.Sprintf("   %s\n", change))
+		}
+	}
+	result.WriteString("\n")
+}
+
+// dryRunArg reads the dry_run argument. Bulk changes are previewed unless the
+// caller explicitly passes dry_run=false.
+func dryRunArg(args map[string]interface{}) bool {
+	if dryRun, ok := args["dry_run"].(bool); ok {
+		return dryRun
+	}
+	return true
+}
+
+// applyPlannedUpdates sends the planned updates to YNAB in a single bulk
+// request, so either every update is applied or none is
+func applyPlannedUpdates(client *ynab.Client, budgetID string, plans []plannedUpdate, progress progressFunc) ([]ynab.Transaction, error) {
+	if progress == nil {
+		progress = logProgress
+	}
+	total := float64(len(plans))
+	progress(0, total, fmt.Sprintf("Applying %d updates", len(plans)))
+
+	req := &ynab.UpdateTransactionsRequest{
+		Transactions: make([]ynab.BulkTransactionUpdate, 0, len(plans)),
+	}
+	for _, plan := range plans {
+		req.Transactions = append(req.Transactions, plan.Update)
+	}
+	updated, err := client.UpdateTransactions(budgetID, req)
+	if err != nil {
+		return nil, err
+	}
+	progress(total, total, fmt.Sprintf("Applied %d updates", len(plans)))
+	return updated, nil
+}
+
+// selectTransactions resolves the transactions targeted by a bulk tool call:
+// explicit transaction_ids (fetched one by one and narrowed by the filter) or
+// every transaction matching the filter. Returns the IDs that could not be
+// found or were excluded by the filter.
+func selectTransactions(client *ynab.Client, budgetID string, args map[string]interface{}, filter *transactionFilter) ([]ynab.Transaction, []string, error) {
+	ids := stringSliceArg(args, "transaction_ids")
+	if len(ids) == 0 {
+		transactions, err := fetchFilteredTransactions(client, budgetID, filter)
+		if err != nil {
+			return nil, nil, err
+		}
+		return transactions, nil, nil
+	}
+
+	if err := filter.resolveCategoryGroup(client, budgetID); err != nil {
+		return nil, nil, err
+	}
+	selected := make([]ynab.Transaction, 0, len(ids))
+	missing := make([]string, 0)
+	for _, id := range ids {
+		tx, err := client.GetTransaction(budgetID, id)
+		if ynab.IsNotFound(err) {
+			missing = append(missing, id)
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if !filter.matches(*tx) || !filter.matchesType(*tx) {
+			missing = append(missing, id)
+			continue
+		}
+		selected = append(selected, *tx)
+	}
+	return selected, missing, nil
+}
+
+// stringSliceArg reads an array of strings from tool arguments
+func stringSliceArg(args map[string]interface{}, name string) []string {
+	raw, ok := args[name].([]interface{})
+	if !ok {
+		return nil
+	}
+	values := make([]string, 0, len(raw))
+	for _, item := range raw {
+		if value, ok := item.(string); ok && value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// runBulkChange plans, previews and (unless dryRun) applies a change to the
+// selected transactions, returning the tool's text output
+func runBulkChange(client *ynab.Client, budgetID string, selected []ynab.Transaction, missing []string, change bulkChange, dryRun bool, progress progressFunc) (*mcp.CallToolResult, error) {
+	categoryNames := map[string]string{}
+	if change.CategoryID != "" {
+		categoryGroups, err := client.ListCategories(budgetID)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch categories: %v", err)), nil
+		}
+		categoryNames = categoryNameIndex(categoryGroups)
+		if _, ok := categoryNames[change.CategoryID]; !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("category not found: %s", change.CategoryID)), nil
+		}
+	}
+
+	plans := make([]plannedUpdate, 0, len(selected))
+	unchanged := 0
+	splits := make([]string, 0)
+	for _, tx := range selected {
+		txChange := change
+		// A split's categories live on its lines; YNAB would replace them
+		if change.CategoryID != "" && len(tx.Subtransactions) > 0 {
+			splits = append(splits, tx.ID)
+			txChange.CategoryID = ""
+		}
+		plan, ok := planBulkChange(tx, txChange, categoryNames)
+		if !ok {
+			if txChange.CategoryID == change.CategoryID {
+				unchanged++
+			}
+			continue
+		}
+		plans = append(plans, plan)
+	}
+
+	var result strings.Builder
+	if dryRun {
+		result.WriteString(fmt.Sprintf("Dry run: %d transaction(s) would be updated", len(plans)))
+	} else {
+		result.WriteString(fmt.Sprintf("%d transaction(s) to update", len(plans)))
+	}
+	if unchanged > 0 {
+		result.WriteString(fmt.Sprintf(", %d already up to date", unchanged))
+	}
+	result.WriteString(":\n\n")
+
+	if len(missing) > 0 {
+		result.WriteString(fmt.Sprintf("⚠️  %d transaction ID(s) not found or excluded by filters: %s\n\n",
+			len(missing), strings.Join(missing, ", ")))
+	}
+
+	if len(splits) > 0 {
+		result.WriteString(fmt.Sprintf("⚠️  Category not changed on %d split transaction(s); recategorize their lines individually: %s\n\n",
+			len(splits), strings.Join(splits, ", ")))
+	}
+
+	if len(plans) == 0 {
+		result.WriteString("Nothing to update.\n")
+		return mcp.NewToolResultText(result.String()), nil
+	}
+
+	writeUpdatePreview(&result, plans)
+
+	if dryRun {
+		result.WriteString("No changes were made. Call again with dry_run=false to apply.\n")
+		return mcp.NewToolResultText(result.String()), nil
+	}
+
+	updated, err := applyPlannedUpdates(client, budgetID, plans, progress)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to update transactions: %v", err)), nil
+	}
+
+	result.WriteString(fmt.Sprintf("Updated %d transaction(s) successfully!\n", len(updated)))
+	return mcp.NewToolResultText(result.String()), nil
+}
+
+// NewBulkUpdateTransactionsTool creates the bulk_update_transactions tool
+func NewBulkUpdateTransactionsTool(client *ynab.Client) ToolDefinition {
+	properties := transactionFilterProperties()
+	properties["budget_id"] = map[string]interface{}{
+		"type":        "string",
+		"description": "The ID of the budget",
+	}
+	properties["transaction_ids"] = map[string]interface{}{
+		"type":        "array",
+		"description": "Optional: IDs of the transactions to update. If omitted, every transaction matching the filters is updated.",
+		"items": map[string]interface{}{
+			"type": "string",
+		},
+	}
+	properties["set_category_id"] = map[string]interface{}{
+		"type":        "string",
+		"description": "Optional: category ID to assign",
+	}
+	properties["set_approved"] = map[string]interface{}{
+		"type":        "boolean",
+		"description": "Optional: approval status to set",
+	}
+	properties["set_flag_color"] = map[string]interface{}{
+		"type":        "string",
+		"description": "Optional: flag color to set",
+		"enum":        []string{"red", "orange", "yellow", "green", "blue", "purple"},
+	}
+	properties["set_cleared"] = map[string]interface{}{
+		"type":        "string",
+		"description": "Optional: cleared status to set",
+		"enum":        []string{"cleared", "uncleared", "reconciled"},
+	}
+	properties["dry_run"] = map[string]interface{}{
+		"type":        "boolean",
+		"description": "Optional: preview the changes without applying them (default true; pass false to apply)",
+	}
+
+	tool := mcp.Tool{
+		Name:        "bulk_update_transactions",
+		Description: "Apply category, approval, flag or cleared changes to many transactions in a single API call. Select transactions by transaction_ids and/or the same filters as search_transactions (e.g., type='uncategorized'). Changes are previewed by default; call again with dry_run=false to apply them.",
+		InputSchema: mcp.ToolInputSchema{
+			Type:       "object",
+			Properties: properties,
+			Required:   []string{"budget_id"},
+		},
+	}
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		budgetID, ok := args["budget_id"].(string)
+		if !ok || budgetID == "" {
+			return mcp.NewToolResultError("budget_id is required"), nil
+		}
+
+		filter, err := parseTransactionFilter(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		if len(stringSliceArg(args, "transaction_ids")) == 0 && !filter.hasSelection() {
+			return mcp.NewToolResultError("transaction_ids or at least one filter is required"), nil
+		}
+
+		change := bulkChange{}
+		if categoryID, ok := args["set_category_id"].(string); ok {
+			change.CategoryID = categoryID
+		}
+		if approved, ok := args["set_approved"].(bool); ok {
+			change.Approved = &approved
+		}
+		if flagColor, ok := args["set_flag_color"].(string); ok {
+			change.FlagColor = flagColor
+		}
+		if cleared, ok := args["set_cleared"].(string); ok {
+			change.Cleared = cleared
+		}
+		if change.isEmpty() {
+			return mcp.NewToolResultError("at least one of set_category_id, set_approved, set_flag_color or set_cleared is required"), nil
+		}
+
+		dryRun := dryRunArg(args)
+
+		selected, missing, err := selectTransactions(client, budgetID, args, filter)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch transactions: %v", err)), nil
+		}
+
+		return runBulkChange(client, budgetID, selected, missing, change, dryRun, newProgressReporter(ctx, request))
+	}
+
+	return ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// NewApproveTransactionsTool creates the approve_transactions tool
+func NewApproveTransactionsTool(client *ynab.Client) ToolDefinition {
+	tool := mcp.Tool{
+		Name:        "approve_transactions",
+		Description: "Approve many unapproved transactions in a single API call. Approves the given transaction_ids, or every unapproved transaction matching the account, date and payee filters (transaction_ids or at least one filter is required). Changes are previewed by default; call again with dry_run=false to apply them.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"budget_id": map[string]interface{}{
+					"type":        "string",
+					"description": "The ID of the budget",
+				},
+				"transaction_ids": map[string]interface{}{
+					"type":        "array",
+					"description": "Optional: IDs of the transactions to approve. If omitted, all unapproved transactions matching the filters are approved.",
+					"items": map[string]interface{}{
+						"type": "string",
+					},
+				},
+				"account_id": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional: only transactions in this account",
+				},
+				"since_date": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional: only transactions on or after this date (YYYY-MM-DD)",
+				},
+				"until_date": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional: only transactions on or before this date (YYYY-MM-DD)",
+				},
+				"payee": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional: case-insensitive substring of the payee name",
+				},
+				"dry_run": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Optional: preview the changes without applying them (default true; pass false to apply)",
+				},
+			},
+			Required: []string{"budget_id"},
+		},
+	}
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		budgetID, ok := args["budget_id"].(string)
+		if !ok || budgetID == "" {
+			return mcp.NewToolResultError("budget_id is required"), nil
+		}
+
+		filter, err := parseTransactionFilter(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if len(stringSliceArg(args, "transaction_ids")) == 0 && !filter.hasSelection() {
+			return mcp.NewToolResultError("transaction_ids or at least one filter is required"), nil
+		}
+		filter.Type = "unapproved"
+
+		dryRun := dryRunArg(args)
+		approved := true
+
+		selected, missing, err := selectTransactions(client, budgetID, args, filter)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch transactions: %v", err)), nil
+		}
+
+		return runBulkChange(client, budgetID, selected, missing, bulkChange{Approved: &approved}, dryRun, newProgressReporter(ctx, request))
+	}
+
+	return ToolDefinition{Tool: tool, Handler: handler}
+}
@@ -0,0 +1,55 @@
+package tools
+
+import "time"
+
+// cadence describes a recurring interval such as weekly or monthly
+type cadence struct {
+	Name     string
+	MinDays  float64 // shortest median interval classified as this cadence
+	MaxDays  float64 // longest median interval classified as this cadence
+	PerMonth float64 // occurrences per month, for monthly-equivalent amounts
+	months   int     // calendar months between occurrences (0 for day-based cadences)
+	days     int     // days between occurrences for day-based cadences
+}
+
+// recurringCadences are the cadences recognized when analyzing charge history
+var recurringCadences = []cadence{
+	{Name: "weekly", MinDays: 5, MaxDays: 9, PerMonth: 52.0 / 12.0, days: 7},
+	{Name: "biweekly", MinDays: 12, MaxDays: 14.5, PerMonth: 26.0 / 12.0, days: 14},
+	{Name: "semimonthly", MinDays: 14.5, MaxDays: 17, PerMonth: 2, days: 15},
+	{Name: "monthly", MinDays: 26, MaxDays: 35, PerMonth: 1, months: 1},
+	{Name: "quarterly", MinDays: 80, MaxDays: 100, PerMonth: 1.0 / 3.0, months: 3},
+	{Name: "semiannual", MinDays: 170, MaxDays: 200, PerMonth: 1.0 / 6.0, months: 6},
+	{Name: "annual", MinDays: 340, MaxDays: 390, PerMonth: 1.0 / 12.0, months: 12},
+}
+
+// classifyInterval returns the cadence matching a median interval in days
+func classifyInterval(medianDays float64) (cadence, bool) {
+	for _, c := range recurringCadences {
+		if medianDays >= c.MinDays && medianDays <= c.MaxDays {
+			return c, true
+		}
+	}
+	return cadence{}, false
+}
+
+// next returns the expected occurrence after t
+func (c cadence) next(t time.Time) time.Time {
+	if c.months > 0 {
+		return t.AddDate(0, c.months, 0)
+	}
+	return t.AddDate(0, 0, c.days)
+}
+
+// nominalDays returns the typical number of days between occurrences
+func (c cadence) nominalDays() float64 {
+	if c.months > 0 {
+		return float64(c.months) * 30.44
+	}
+	return float64(c.days)
+}
+
+// daysBetween returns the whole number of days from a to b
+func daysBetween(a, b time.Time) float64 {
+	return b.Sub(a).Hours() / 24
+}
@@ -0,0 +1,310 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jeff-french/ynab-mcp-server/internal/ynab"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const defaultCompareTopN = 20
+
+// dateRange is an inclusive span of YYYY-MM-DD dates
+type dateRange struct {
+	Since string `json:"since"`
+	Until string `json:"until"`
+}
+
+// contains reports whether a YYYY-MM-DD date falls inside the range
+func (r dateRange) contains(date string) bool {
+	return date >= r.Since && date <= r.Until
+}
+
+// comparePresets are the shorthand period pairs compare_periods understands
+var comparePresets = []string{
+	"month_to_date_vs_last_month",
+	"month_to_date_vs_last_year",
+	"last_month_vs_previous_month",
+	"last_month_vs_last_year",
+	"ytd_vs_last_ytd",
+	"last_30_days_vs_previous_30_days",
+}
+
+// resolveComparePreset turns a preset name into current and previous ranges
+// relative to today. Partial periods are compared against the same span of the
+// earlier period, clamped to the end of shorter months.
+func resolveComparePreset(preset string, today time.Time) (dateRange, dateRange, error) {
+	format := func(t time.Time) string { return t.Format("2006-01-02") }
+	thisMonth := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	lastMonth := thisMonth.AddDate(0, -1, 0)
+	endOfMonth := func(month time.Time) time.Time { return month.AddDate(0, 1, -1) }
+
+	switch preset {
+	case "month_to_date_vs_last_month":
+		return dateRange{format(thisMonth), format(today)},
+			dateRange{format(lastMonth), format(addMonthsClamped(lastMonth, 0, today.Day()))}, nil
+	case "month_to_date_vs_last_year":
+		sameMonthLastYear := thisMonth.AddDate(-1, 0, 0)
+		return dateRange{format(thisMonth), format(today)},
+			dateRange{format(sameMonthLastYear), format(addMonthsClamped(sameMonthLastYear, 0, today.Day()))}, nil
+	case "last_month_vs_previous_month":
+		monthBefore := lastMonth.AddDate(0, -1, 0)
+		return dateRange{format(lastMonth), format(endOfMonth(lastMonth))},
+			dateRange{format(monthBefore), format(endOfMonth(monthBefore))}, nil
+	case "last_month_vs_last_year":
+		lastMonthLastYear := lastMonth.AddDate(-1, 0, 0)
+		return dateRange{format(lastMonth), format(endOfMonth(lastMonth))},
+			dateRange{format(lastMonthLastYear), format(endOfMonth(lastMonthLastYear))}, nil
+	case "ytd_vs_last_ytd":
+		startOfYear := time.Date(today.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+		lastYearMonth := time.Date(today.Year()-1, today.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return dateRange{format(startOfYear), format(today)},
+			dateRange{format(startOfYear.AddDate(-1, 0, 0)), format(addMonthsClamped(lastYearMonth, 0, today.Day()))}, nil
+	case "last_30_days_vs_previous_30_days":
+		return dateRange{format(today.AddDate(0, 0, -29)), format(today)},
+			dateRange{format(today.AddDate(0, 0, -59)), format(today.AddDate(0, 0, -30))}, nil
+	default:
+		return dateRange{}, dateRange{}, fmt.Errorf("unknown preset %q (expected one of: %s)", preset, strings.Join(comparePresets, ", "))
+	}
+}
+
+// periodDelta compares one category's or payee's outflow across the two periods
+type periodDelta struct {
+	ID            string   `json:"id,omitempty"`
+	Name          string   `json:"name"`
+	Current       float64  `json:"current"`
+	Previous      float64  `json:"previous"`
+	Change        float64  `json:"change"`
+	ChangePercent *float64 `json:"change_percent"`
+}
+
+// newPeriodDelta computes the absolute and percentage change between periods
+func newPeriodDelta(id, name string, current, previous float64) periodDelta {
+	delta := periodDelta{
+		ID:       id,
+		Name:     name,
+		Current:  roundTo(current, 2),
+		Previous: roundTo(previous, 2),
+		Change:   roundTo(current-previous, 2),
+	}
+	if previous != 0 {
+		percent := roundTo((current-previous)/previous*100, 1)
+		delta.ChangePercent = &percent
+	}
+	return delta
+}
+
+// sortByLargestChange orders deltas by the size of their change, largest first
+func sortByLargestChange(deltas []periodDelta) {
+	sort.Slice(deltas, func(i, j int) bool {
+		return math.Abs(deltas[i].Change) > math.Abs(deltas[j].Change)
+	})
+}
+
+// NewComparePeriodsTool creates the compare_periods tool
+func NewComparePeriodsTool(client *ynab.Client) ToolDefinition {
+	tool := mcp.Tool{
+		Name:        "compare_periods",
+		Description: "Compare spending between two periods, such as this month vs last month or year-to-date vs last year-to-date. Returns per-category and per-payee outflow for each period with absolute and percentage changes sorted by largest change, plus payees that are new or have disappeared. Use a preset or give both date ranges explicitly.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"budget_id": map[string]interface{}{
+					"type":        "string",
+					"description": "The ID of the budget",
+				},
+				"preset": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional: shorthand period pair. Month-to-date and year-to-date presets compare against the same span of the earlier period.",
+					"enum":        comparePresets,
+				},
+				"current_since": map[string]interface{}{
+					"type":        "string",
+					"description": "Start of the current period in YYYY-MM-DD format (required without preset)",
+				},
+				"current_until": map[string]interface{}{
+					"type":        "string",
+					"description": "End of the current period in YYYY-MM-DD format (required without preset)",
+				},
+				"previous_since": map[string]interface{}{
+					"type":        "string",
+					"description": "Start of the period to compare against in YYYY-MM-DD format (required without preset)",
+				},
+				"previous_until": map[string]interface{}{
+					"type":        "string",
+					"description": "End of the period to compare against in YYYY-MM-DD format (required without preset)",
+				},
+				"top_n": map[string]interface{}{
+					"type":        "number",
+					"description": fmt.Sprintf("Optional: number of categories and payees to return (default %d)", defaultCompareTopN),
+				},
+				"transfer_handling": transferHandlingProperty(),
+			},
+			Required: []string{"budget_id"},
+		},
+	}
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		budgetID, ok := args["budget_id"].(string)
+		if !ok || budgetID == "" {
+			return mcp.NewToolResultError("budget_id is required"), nil
+		}
+
+		transferHandling, err := parseTransferHandling(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		var current, previous dateRange
+		if preset, ok := args["preset"].(string); ok && preset != "" {
+			today, _ := parseDate(time.Now().Format("2006-01-02"))
+			current, previous, err = resolveComparePreset(preset, today)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		} else {
+			current.Since, _ = args["current_since"].(string)
+			current.Until, _ = args["current_until"].(string)
+			previous.Since, _ = args["previous_since"].(string)
+			previous.Until, _ = args["previous_until"].(string)
+			if current.Since == "" || current.Until == "" || previous.Since == "" || previous.Until == "" {
+				return mcp.NewToolResultError("Provide a preset or all of current_since, current_until, previous_since and previous_until"), nil
+			}
+		}
+
+		if err := validateDateRange(current.Since, current.Until); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("current period: %v", err)), nil
+		}
+		if err := validateDateRange(previous.Since, previous.Until); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("previous period: %v", err)), nil
+		}
+
+		topN := defaultCompareTopN
+		if topNFloat, ok := args["top_n"].(float64); ok && topNFloat >= 1 {
+			topN = int(topNFloat)
+		}
+
+		onBudget, err := onBudgetAccounts(client, budgetID, transferHandling)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch accounts: %v", err)), nil
+		}
+
+		// One pass covers both periods
+		currentByCategory := newAggregator([]groupingKey{categoryKey})
+		previousByCategory := newAggregator([]groupingKey{categoryKey})
+		currentByPayee := newAggregator([]groupingKey{payeeKey})
+		previousByPayee := newAggregator([]groupingKey{payeeKey})
+		filter := &transactionFilter{
+			SinceDate: min(current.Since, previous.Since),
+			UntilDate: max(current.Until, previous.Until),
+		}
+		_, err = streamAggregationLines(client, budgetID, filter, onBudget, transferHandling, newProgressReporter(ctx, request), func(line ynab.Transaction) {
+			if current.contains(line.Date) {
+				currentByCategory.add(line)
+				currentByPayee.add(line)
+			}
+			if previous.contains(line.Date) {
+				previousByCategory.add(line)
+				previousByPayee.add(line)
+			}
+		})
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		// Categories
+		currentCategories := categorySummaries(currentByCategory.groups)
+		previousCategories := categorySummaries(previousByCategory.groups)
+		categoryDeltas := make([]periodDelta, 0)
+		for id, summary := range currentCategories {
+			previousOutflow := 0.0
+			if before, ok := previousCategories[id]; ok {
+				previousOutflow = before.TotalOutflow
+			}
+			categoryDeltas = append(categoryDeltas, newPeriodDelta(id, summary.CategoryName, summary.TotalOutflow, previousOutflow))
+		}
+		for id, summary := range previousCategories {
+			if _, ok := currentCategories[id]; !ok {
+				categoryDeltas = append(categoryDeltas, newPeriodDelta(id, summary.CategoryName, 0, summary.TotalOutflow))
+			}
+		}
+
+		// Payees
+		currentPayees := payeeSummaries(currentByPayee.groups)
+		previousPayees := payeeSummaries(previousByPayee.groups)
+		payeeDeltas := make([]periodDelta, 0)
+		newPayees := make([]periodDelta, 0)
+		disappearedPayees := make([]periodDelta, 0)
+		for id, summary := range currentPayees {
+			before, ok := previousPayees[id]
+			if !ok {
+				if summary.TotalOutflow > 0 {
+					newPayees = append(newPayees, newPeriodDelta(id, summary.PayeeName, summary.TotalOutflow, 0))
+				}
+				payeeDeltas = append(payeeDeltas, newPeriodDelta(id, summary.PayeeName, summary.TotalOutflow, 0))
+				continue
+			}
+			payeeDeltas = append(payeeDeltas, newPeriodDelta(id, summary.PayeeName, summary.TotalOutflow, before.TotalOutflow))
+		}
+		for id, summary := range previousPayees {
+			if _, ok := currentPayees[id]; ok {
+				continue
+			}
+			if summary.TotalOutflow > 0 {
+				disappearedPayees = append(disappearedPayees, newPeriodDelta(id, summary.PayeeName, 0, summary.TotalOutflow))
+			}
+			payeeDeltas = append(payeeDeltas, newPeriodDelta(id, summary.PayeeName, 0, summary.TotalOutflow))
+		}
+
+		for _, deltas := range [][]periodDelta{categoryDeltas, payeeDeltas, newPayees, disappearedPayees} {
+			sortByLargestChange(deltas)
+		}
+		if len(categoryDeltas) > topN {
+			categoryDeltas = categoryDeltas[:topN]
+		}
+		if len(payeeDeltas) > topN {
+			payeeDeltas = payeeDeltas[:topN]
+		}
+
+		// Overall totals for both periods
+		totalCurrent := 0.0
+		for _, summary := range currentCategories {
+			totalCurrent += summary.TotalOutflow
+		}
+		totalPrevious := 0.0
+		for _, summary := range previousCategories {
+			totalPrevious += summary.TotalOutflow
+		}
+
+		// Build result
+		result := map[string]interface{}{
+			"current_period":     current,
+			"previous_period":    previous,
+			"total_outflow":      newPeriodDelta("", "Total", totalCurrent, totalPrevious),
+			"categories":         categoryDeltas,
+			"payees":             payeeDeltas,
+			"new_payees":         newPayees,
+			"disappeared_payees": disappearedPayees,
+		}
+
+		jsonResult, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to format result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonResult)), nil
+	}
+
+	return ToolDefinition{Tool: tool, Handler: handler}
+}
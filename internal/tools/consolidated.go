@@ -0,0 +1,258 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jeff-french/ynab-mcp-server/internal/ynab"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// overviewTotals are the headline figures for one or more budgets
+type overviewTotals struct {
+	Assets      float64 `json:"assets"`
+	Liabilities float64 `json:"liabilities"`
+	NetWorth    float64 `json:"net_worth"`
+	Income      float64 `json:"income"`
+	Spending    float64 `json:"spending"`
+}
+
+// add accumulates other scaled by rate
+func (t *overviewTotals) add(other overviewTotals, rate float64) {
+	t.Assets += other.Assets * rate
+	t.Liabilities += other.Liabilities * rate
+	t.NetWorth += other.NetWorth * rate
+	t.Income += other.Income * rate
+	t.Spending += other.Spending * rate
+}
+
+// rounded returns the totals rounded to cents
+func (t overviewTotals) rounded() overviewTotals {
+	return overviewTotals{
+		Assets:      roundTo(t.Assets, 2),
+		Liabilities: roundTo(t.Liabilities, 2),
+		NetWorth:    roundTo(t.NetWorth, 2),
+		Income:      roundTo(t.Income, 2),
+		Spending:    roundTo(t.Spending, 2),
+	}
+}
+
+// budgetOverview summarizes one budget in its own currency, plus the same
+// figures converted to the base currency when a rate is known
+type budgetOverview struct {
+	BudgetID        string             `json:"budget_id"`
+	BudgetName      string             `json:"budget_name"`
+	Currency        string             `json:"currency"`
+	ExchangeRate    *float64           `json:"exchange_rate"`
+	Accounts        int                `json:"accounts"`
+	Totals          overviewTotals     `json:"totals"`
+	BalancesByType  map[string]float64 `json:"balances_by_account_type"`
+	ConvertedTotals *overviewTotals    `json:"converted_totals,omitempty"`
+}
+
+// budgetCurrency returns a budget's ISO currency code, or "" if unknown
+func budgetCurrency(budget ynab.Budget) string {
+	if budget.CurrencyFormat == nil {
+		return ""
+	}
+	return strings.ToUpper(budget.CurrencyFormat.ISOCode)
+}
+
+// exchangeRate returns the value of one unit of currency in the base currency.
+// A budget without a known currency is assumed to use the base currency.
+func exchangeRate(currency, base string, rates map[string]float64) (float64, bool) {
+	if currency == "" || currency == base {
+		return 1, true
+	}
+	rate, ok := rates[currency]
+	return rate, ok
+}
+
+// summarizeBudget totals a budget's open accounts and one month's income and spending
+func summarizeBudget(client *ynab.Client, budget ynab.Budget, month string) (*budgetOverview, error) {
+	accounts, err := client.ListAccounts(budget.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch accounts for %s: %w", budget.Name, err)
+	}
+	budgetMonth, err := client.GetMonth(budget.ID, month+"-01")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch month %s for %s: %w", month, budget.Name, err)
+	}
+
+	overview := &budgetOverview{
+		BudgetID:       budget.ID,
+		BudgetName:     budget.Name,
+		Currency:       budgetCurrency(budget),
+		BalancesByType: make(map[string]float64),
+	}
+	for _, account := range accounts {
+		if account.Deleted || account.Closed {
+			continue
+		}
+		balance := ynab.MilliunitsToFloat(account.Balance)
+		overview.Accounts++
+		overview.BalancesByType[account.Type] += balance
+		if isLiabilityAccount(account.Type) {
+			overview.Totals.Liabilities += -balance
+		} else {
+			overview.Totals.Assets += balance
+		}
+		overview.Totals.NetWorth += balance
+	}
+	for accountType, balance := range overview.BalancesByType {
+		overview.BalancesByType[accountType] = roundTo(balance, 2)
+	}
+
+	// Month activity is negative for spending
+	overview.Totals.Income = ynab.MilliunitsToFloat(budgetMonth.Income)
+	overview.Totals.Spending = -ynab.MilliunitsToFloat(budgetMonth.Activity)
+	overview.Totals = overview.Totals.rounded()
+	return overview, nil
+}
+
+// NewGetConsolidatedOverviewTool creates the get_consolidated_overview tool.
+// Budgets in other currencies are converted to baseCurrency with exchangeRates
+// from the config file.
+func NewGetConsolidatedOverviewTool(client *ynab.Client, baseCurrency string, exchangeRates map[string]float64) ToolDefinition {
+	tool := mcp.Tool{
+		Name:        "get_consolidated_overview",
+		Description: "Combined picture across several budgets (e.g. personal and business): open accounts, assets, liabilities, net worth, and a month's income and spending per budget and in total. Budgets in other currencies are converted with the exchange rates in the server config; budgets without a rate are reported but left out of the combined totals.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"budget_ids": map[string]interface{}{
+					"type":        "array",
+					"description": "Optional: budgets to include (default: every budget)",
+					"items": map[string]interface{}{
+						"type": "string",
+					},
+				},
+				"month": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional: month for income and spending in YYYY-MM format (default: current month)",
+				},
+				"base_currency": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional: ISO currency code for combined totals (default: base_currency from the config, or the first budget's currency)",
+				},
+			},
+			Required: []string{},
+		},
+	}
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			args = map[string]interface{}{}
+		}
+
+		month := getCurrentMonth()
+		if monthArg, ok := args["month"].(string); ok && monthArg != "" {
+			month = monthArg
+		}
+		if _, err := parseMonth(month); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid month format: %v", err)), nil
+		}
+
+		budgets, err := client.ListBudgets()
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch budgets: %v", err)), nil
+		}
+
+		if ids := stringSliceArg(args, "budget_ids"); len(ids) > 0 {
+			byID := make(map[string]ynab.Budget, len(budgets))
+			for _, budget := range budgets {
+				byID[budget.ID] = budget
+			}
+			selected := make([]ynab.Budget, 0, len(ids))
+			for _, id := range ids {
+				budget, found := byID[id]
+				if !found {
+					return mcp.NewToolResultError(fmt.Sprintf("budget not found: %s", id)), nil
+				}
+				selected = append(selected, budget)
+			}
+			budgets = selected
+		}
+		if len(budgets) == 0 {
+			return mcp.NewToolResultError("No budgets found"), nil
+		}
+
+		base := baseCurrency
+		if baseArg, ok := args["base_currency"].(string); ok && baseArg != "" {
+			base = strings.ToUpper(baseArg)
+		}
+		if base == "" {
+			base = budgetCurrency(budgets[0])
+		}
+
+		overviews := make([]*budgetOverview, 0, len(budgets))
+		combined := overviewTotals{}
+		combinedByType := make(map[string]float64)
+		combinedAccounts := 0
+		missingRates := make(map[string]bool)
+		for _, budget := range budgets {
+			overview, err := summarizeBudget(client, budget, month)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Failed to summarize budget: %v", err)), nil
+			}
+			overviews = append(overviews, overview)
+
+			rate, ok := exchangeRate(overview.Currency, base, exchangeRates)
+			if !ok {
+				missingRates[overview.Currency] = true
+				continue
+			}
+			overview.ExchangeRate = &rate
+			if rate != 1 {
+				converted := overviewTotals{}
+				converted.add(overview.Totals, rate)
+				converted = converted.rounded()
+				overview.ConvertedTotals = &converted
+			}
+
+			combined.add(overview.Totals, rate)
+			combinedAccounts += overview.Accounts
+			for accountType, balance := range overview.BalancesByType {
+				combinedByType[accountType] += balance * rate
+			}
+		}
+		for accountType, balance := range combinedByType {
+			combinedByType[accountType] = roundTo(balance, 2)
+		}
+
+		// Build result
+		result := map[string]interface{}{
+			"month":         month,
+			"base_currency": base,
+			"budgets":       overviews,
+			"combined": map[string]interface{}{
+				"accounts":                 combinedAccounts,
+				"totals":                   combined.rounded(),
+				"balances_by_account_type": combinedByType,
+			},
+		}
+		if len(missingRates) > 0 {
+			missing := make([]string, 0, len(missingRates))
+			for currency := range missingRates {
+				missing = append(missing, currency)
+			}
+			sort.Strings(missing)
+			result["missing_exchange_rates"] = missing
+			result["note"] = fmt.Sprintf("Budgets in %s are excluded from the combined totals. Add their rates to exchange_rates in the config file.",
+				strings.Join(missing, ", "))
+		}
+
+		jsonResult, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to format result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonResult)), nil
+	}
+
+	return ToolDefinition{Tool: tool, Handler: handler}
+}
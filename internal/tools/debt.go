@@ -0,0 +1,453 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/jeff-french/ynab-mcp-server/internal/ynab"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// maxPayoffMonths stops simulations whose payments never catch up with interest
+	maxPayoffMonths = 600
+
+	// Fallback minimum payment when neither the caller nor YNAB supplies one:
+	// the larger of a percentage of the starting balance and a fixed floor
+	defaultMinimumPaymentPercent = 0.02
+	defaultMinimumPaymentFloor   = 25.0
+)
+
+// debt is one liability account being paid down in a simulation
+type debt struct {
+	AccountID      string
+	AccountName    string
+	AccountType    string
+	Balance        float64
+	InterestRate   float64 // APR as a percentage
+	MinimumPayment float64
+	RateSource     string
+	MinimumSource  string
+}
+
+// debtPayoff is when and at what cost one debt is paid off under a strategy
+type debtPayoff struct {
+	AccountName  string  `json:"account_name"`
+	PayoffMonth  string  `json:"payoff_month,omitempty"`
+	Months       int     `json:"months"`
+	InterestPaid float64 `json:"interest_paid"`
+	PaidOff      bool    `json:"paid_off"`
+}
+
+// payoffPlan is the outcome of simulating a payoff strategy
+type payoffPlan struct {
+	Strategy      string       `json:"strategy"`
+	DebtFree      bool         `json:"debt_free"`
+	Months        int          `json:"months"`
+	DebtFreeMonth string       `json:"debt_free_month,omitempty"`
+	TotalInterest float64      `json:"total_interest"`
+	TotalPaid     float64      `json:"total_paid"`
+	PayoffOrder   []debtPayoff `json:"payoff_order"`
+}
+
+// latestDebtValue returns the value that took effect most recently
+func latestDebtValue(values map[string]int64) (int64, bool) {
+	latest := ""
+	for date := range values {
+		if date > latest {
+			latest = date
+		}
+	}
+	if latest == "" {
+		return 0, false
+	}
+	return values[latest], true
+}
+
+// simulatePayoff pays debts down month by month: every debt gets its minimum
+// and whatever is left of monthlyPayment goes to debts in priority order.
+// Minimums freed up by paid-off debts roll into the extra payment.
+func simulatePayoff(strategy string, debts []debt, monthlyPayment float64, start time.Time) payoffPlan {
+	balances := make([]float64, len(debts))
+	interest := make([]float64, len(debts))
+	paidOffAt := make([]int, len(debts))
+	for i, d := range debts {
+		balances[i] = d.Balance
+	}
+
+	order := make([]int, len(debts))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		da, db := debts[order[a]], debts[order[b]]
+		if strategy == "avalanche" && da.InterestRate != db.InterestRate {
+			return da.InterestRate > db.InterestRate
+		}
+		return da.Balance < db.Balance
+	})
+
+	plan := payoffPlan{Strategy: strategy}
+	remaining := len(debts)
+	month := 0
+	for remaining > 0 && month < maxPayoffMonths {
+		month++
+
+		for i, d := range debts {
+			if balances[i] <= 0 {
+				continue
+			}
+			charge := balances[i] * d.InterestRate / 100 / 12
+			balances[i] += charge
+			interest[i] += charge
+			plan.TotalInterest += charge
+		}
+
+		available := monthlyPayment
+		for i, d := range debts {
+			if balances[i] <= 0 {
+				continue
+			}
+			payment := math.Min(d.MinimumPayment, balances[i])
+			balances[i] -= payment
+			available -= payment
+			plan.TotalPaid += payment
+		}
+
+		for _, i := range order {
+			if available <= 0 {
+				break
+			}
+			if balances[i] <= 0 {
+				continue
+			}
+			payment := math.Min(available, balances[i])
+			balances[i] -= payment
+			available -= payment
+			plan.TotalPaid += payment
+		}
+
+		for i := range debts {
+			if paidOffAt[i] == 0 && balances[i] < 0.005 {
+				balances[i] = 0
+				paidOffAt[i] = month
+				remaining--
+			}
+		}
+	}
+
+	plan.DebtFree = remaining == 0
+	plan.Months = month
+	if plan.DebtFree {
+		plan.DebtFreeMonth = getMonthString(start.AddDate(0, month, 0))
+	}
+
+	plan.PayoffOrder = make([]debtPayoff, 0, len(debts))
+	for i, d := range debts {
+		payoff := debtPayoff{
+			AccountName:  d.AccountName,
+			InterestPaid: roundTo(interest[i], 2),
+			PaidOff:      paidOffAt[i] > 0,
+			Months:       paidOffAt[i],
+		}
+		if payoff.PaidOff {
+			payoff.PayoffMonth = getMonthString(start.AddDate(0, paidOffAt[i], 0))
+		}
+		plan.PayoffOrder = append(plan.PayoffOrder, payoff)
+	}
+	sort.SliceStable(plan.PayoffOrder, func(a, b int) bool {
+		pa, pb := plan.PayoffOrder[a], plan.PayoffOrder[b]
+		if pa.PaidOff != pb.PaidOff {
+			return pa.PaidOff
+		}
+		return pa.Months < pb.Months
+	})
+
+	plan.TotalInterest = roundTo(plan.TotalInterest, 2)
+	plan.TotalPaid = roundTo(plan.TotalPaid, 2)
+	return plan
+}
+
+// debtOverrides holds caller-supplied interest rates and minimum payments by account ID
+type debtOverrides struct {
+	InterestRate   *float64
+	MinimumPayment *float64
+}
+
+// parseDebtOverrides reads the debts argument: [{account_id, interest_rate, minimum_payment}]
+func parseDebtOverrides(args map[string]interface{}) (map[string]debtOverrides, error) {
+	overrides := make(map[string]debtOverrides)
+	raw, ok := args["debts"].([]interface{})
+	if !ok {
+		return overrides, nil
+	}
+	for i, item := range raw {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("debts[%d] must be an object", i)
+		}
+		accountID, ok := entry["account_id"].(string)
+		if !ok || accountID == "" {
+			return nil, fmt.Errorf("debts[%d].account_id is required", i)
+		}
+		var override debtOverrides
+		if rate, ok := entry["interest_rate"].(float64); ok {
+			if rate < 0 {
+				return nil, fmt.Errorf("debts[%d].interest_rate cannot be negative", i)
+			}
+			override.InterestRate = &rate
+		}
+		if minimum, ok := entry["minimum_payment"].(float64); ok {
+			if minimum < 0 {
+				return nil, fmt.Errorf("debts[%d].minimum_payment cannot be negative", i)
+			}
+			override.MinimumPayment = &minimum
+		}
+		overrides[accountID] = override
+	}
+	return overrides, nil
+}
+
+// newDebt builds a simulation debt from an account, preferring caller-supplied
+// terms over the ones YNAB stores on loan accounts
+func newDebt(account ynab.Account, override debtOverrides) debt {
+	d := debt{
+		AccountID:   account.ID,
+		AccountName: account.Name,
+		AccountType: account.Type,
+		Balance:     -ynab.MilliunitsToFloat(account.Balance),
+		RateSource:  "unknown",
+	}
+
+	if override.InterestRate != nil {
+		d.InterestRate = *override.InterestRate
+		d.RateSource = "provided"
+	} else if rate, ok := latestDebtValue(account.DebtInterestRates); ok {
+		d.InterestRate = float64(rate) / 1000
+		d.RateSource = "ynab"
+	}
+
+	if override.MinimumPayment != nil {
+		d.MinimumPayment = *override.MinimumPayment
+		d.MinimumSource = "provided"
+	} else if minimum, ok := latestDebtValue(account.DebtMinimumPayments); ok && minimum > 0 {
+		d.MinimumPayment = ynab.MilliunitsToFloat(minimum)
+		d.MinimumSource = "ynab"
+	} else {
+		d.MinimumPayment = roundTo(math.Max(d.Balance*defaultMinimumPaymentPercent, defaultMinimumPaymentFloor), 2)
+		d.MinimumSource = "estimated"
+	}
+
+	return d
+}
+
+// NewPlanDebtPayoffTool creates the plan_debt_payoff tool
+func NewPlanDebtPayoffTool(client *ynab.Client) ToolDefinition {
+	tool := mcp.Tool{
+		Name:        "plan_debt_payoff",
+		Description: "Plan paying off credit cards and loans. Simulates avalanche (highest interest first) and snowball (smallest balance first) schedules for a monthly payment budget, using interest rates and minimum payments supplied by the caller or stored on YNAB loan accounts. Reports payoff dates and total interest for each strategy, and whether each credit card's payment category covers the card balance.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"budget_id": map[string]interface{}{
+					"type":        "string",
+					"description": "The ID of the budget",
+				},
+				"monthly_payment": map[string]interface{}{
+					"type":        "number",
+					"description": "Total amount available for debt payments each month (must cover all minimum payments)",
+				},
+				"debts": map[string]interface{}{
+					"type":        "array",
+					"description": fmt.Sprintf("Optional: interest rates (APR %%) and minimum payments per account. Missing rates use YNAB's loan details or 0; missing minimums use YNAB's or the larger of %.0f%% of the balance and %.0f.", defaultMinimumPaymentPercent*100, defaultMinimumPaymentFloor),
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"account_id": map[string]interface{}{
+								"type": "string",
+							},
+							"interest_rate": map[string]interface{}{
+								"type":        "number",
+								"description": "Annual percentage rate, e.g. 22.99",
+							},
+							"minimum_payment": map[string]interface{}{
+								"type": "number",
+							},
+						},
+						"required": []string{"account_id"},
+					},
+				},
+				"account_ids": map[string]interface{}{
+					"type":        "array",
+					"description": "Optional: only plan for these accounts (default: every open debt account with a balance owed)",
+					"items": map[string]interface{}{
+						"type": "string",
+					},
+				},
+			},
+			Required: []string{"budget_id", "monthly_payment"},
+		},
+	}
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		budgetID, ok := args["budget_id"].(string)
+		if !ok || budgetID == "" {
+			return mcp.NewToolResultError("budget_id is required"), nil
+		}
+
+		monthlyPayment, ok := args["monthly_payment"].(float64)
+		if !ok || monthlyPayment <= 0 {
+			return mcp.NewToolResultError("monthly_payment is required and must be positive"), nil
+		}
+
+		overrides, err := parseDebtOverrides(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		onlyAccounts := make(map[string]bool)
+		for _, id := range stringSliceArg(args, "account_ids") {
+			onlyAccounts[id] = true
+		}
+
+		accounts, err := client.ListAccounts(budgetID)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch accounts: %v", err)), nil
+		}
+
+		debts := make([]debt, 0)
+		creditCards := make([]ynab.Account, 0)
+		for _, account := range accounts {
+			if account.Deleted || account.Closed || !isLiabilityAccount(account.Type) {
+				continue
+			}
+			if len(onlyAccounts) > 0 && !onlyAccounts[account.ID] {
+				continue
+			}
+			if account.Type == "creditCard" {
+				creditCards = append(creditCards, account)
+			}
+			// Liability balances are negative while money is owed
+			if account.Balance >= 0 {
+				continue
+			}
+			debts = append(debts, newDebt(account, overrides[account.ID]))
+		}
+
+		if len(debts) == 0 {
+			return mcp.NewToolResultError("No debt accounts with a balance owed were found"), nil
+		}
+
+		totalBalance := 0.0
+		totalMinimum := 0.0
+		unknownRates := make([]string, 0)
+		debtDetails := make([]map[string]interface{}, 0, len(debts))
+		for _, d := range debts {
+			totalBalance += d.Balance
+			totalMinimum += d.MinimumPayment
+			if d.RateSource == "unknown" {
+				unknownRates = append(unknownRates, d.AccountName)
+			}
+			debtDetails = append(debtDetails, map[string]interface{}{
+				"account_id":             d.AccountID,
+				"account_name":           d.AccountName,
+				"account_type":           d.AccountType,
+				"balance":                roundTo(d.Balance, 2),
+				"interest_rate":          d.InterestRate,
+				"interest_rate_source":   d.RateSource,
+				"minimum_payment":        d.MinimumPayment,
+				"minimum_payment_source": d.MinimumSource,
+			})
+		}
+
+		if monthlyPayment < totalMinimum {
+			return mcp.NewToolResultError(fmt.Sprintf("monthly_payment %s does not cover the minimum payments of %s",
+				ynab.FormatCurrency(ynab.FloatToMilliunits(monthlyPayment)), ynab.FormatCurrency(ynab.FloatToMilliunits(totalMinimum)))), nil
+		}
+
+		start, _ := parseMonth(getCurrentMonth())
+		avalanche := simulatePayoff("avalanche", debts, monthlyPayment, start)
+		snowball := simulatePayoff("snowball", debts, monthlyPayment, start)
+
+		recommended := "avalanche"
+		if snowball.DebtFree && (!avalanche.DebtFree || snowball.TotalInterest < avalanche.TotalInterest) {
+			recommended = "snowball"
+		}
+
+		// Credit card payment categories live in the Credit Card Payments group, named after the card
+		coverage := make([]map[string]interface{}, 0)
+		if len(creditCards) > 0 {
+			groups, err := client.ListCategories(budgetID)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch categories: %v", err)), nil
+			}
+			paymentCategories := make(map[string]ynab.Category)
+			for _, group := range groups {
+				if group.Deleted || group.Name != creditCardPaymentsCategoryGroup {
+					continue
+				}
+				for _, category := range group.Categories {
+					if !category.Deleted {
+						paymentCategories[category.Name] = category
+					}
+				}
+			}
+
+			for _, card := range creditCards {
+				owed := -card.Balance
+				entry := map[string]interface{}{
+					"account_name": card.Name,
+					"card_balance": ynab.MilliunitsToFloat(owed),
+				}
+				category, found := paymentCategories[card.Name]
+				if !found {
+					entry["payment_category_found"] = false
+					coverage = append(coverage, entry)
+					continue
+				}
+				entry["payment_category_found"] = true
+				entry["payment_available"] = ynab.MilliunitsToFloat(category.Balance)
+				entry["covered"] = category.Balance >= owed
+				if category.Balance < owed {
+					entry["shortfall"] = ynab.MilliunitsToFloat(owed - category.Balance)
+				}
+				coverage = append(coverage, entry)
+			}
+		}
+
+		// Build result
+		result := map[string]interface{}{
+			"debts":                       debtDetails,
+			"total_balance":               roundTo(totalBalance, 2),
+			"total_minimum_payment":       roundTo(totalMinimum, 2),
+			"monthly_payment":             monthlyPayment,
+			"avalanche":                   avalanche,
+			"snowball":                    snowball,
+			"recommended_strategy":        recommended,
+			"interest_saved_by_avalanche": roundTo(snowball.TotalInterest-avalanche.TotalInterest, 2),
+			"credit_card_coverage":        coverage,
+		}
+		if len(unknownRates) > 0 {
+			result["missing_interest_rates"] = unknownRates
+			result["note"] = "Accounts without an interest rate were simulated at 0%; pass debts[].interest_rate for accurate interest totals."
+		}
+
+		jsonResult, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to format result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonResult)), nil
+	}
+
+	return ToolDefinition{Tool: tool, Handler: handler}
+}
@@ -0,0 +1,498 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jeff-french/ynab-mcp-server/internal/ynab"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	defaultDuplicateDayWindow     = 3
+	maxDuplicateDayWindow         = 14
+	defaultDuplicateMinSimilarity = 0.6
+)
+
+// Duplicate actions accepted by find_duplicate_transactions
+const (
+	duplicateActionNone   = "none"
+	duplicateActionFlag   = "flag"
+	duplicateActionDelete = "delete"
+)
+
+// importedPairPenalty scales the score of two separately imported transactions
+const importedPairPenalty = 0.8
+
+// duplicateGroup is a set of transactions that look like the same real-world charge
+type duplicateGroup struct {
+	Amount     float64              `json:"amount"`
+	Similarity float64              `json:"similarity"`
+	Keep       transactionSummary   `json:"keep"`
+	Extras     []transactionSummary `json:"extras"`
+	Reasons    []string             `json:"reasons"`
+
+	extras []ynab.Transaction
+}
+
+// duplicateScore scores how likely two transactions with the same amount and
+// account are duplicates. Returns 0 when YNAB already knows they are distinct
+// (one was matched to the other). The score blends payee name similarity
+// (70%) with date proximity (30%) and is lowered by importedPairPenalty when
+// both came from separate bank imports, since those are sometimes two genuine
+// charges. The penalty stays small enough that a bank posting the same charge
+// twice still clears the default threshold.
+func duplicateScore(a, b ynab.Transaction, dayWindow int) (float64, []string) {
+	if a.MatchedTransactionID == b.ID || b.MatchedTransactionID == a.ID {
+		return 0, nil
+	}
+	if a.ImportID != "" && a.ImportID == b.ImportID {
+		return 1, []string{"same import ID"}
+	}
+
+	dateA, errA := parseDate(a.Date)
+	dateB, errB := parseDate(b.Date)
+	if errA != nil || errB != nil {
+		return 0, nil
+	}
+	dayDiff := int(dateA.Sub(dateB).Hours() / 24)
+	if dayDiff < 0 {
+		dayDiff = -dayDiff
+	}
+	if dayDiff > dayWindow {
+		return 0, nil
+	}
+
+	// Compare every name we have for each side and keep the best match
+	namesA := []string{a.PayeeName, importPayeeName(a)}
+	namesB := []string{b.PayeeName, importPayeeName(b)}
+	nameScore := 0.0
+	for _, nameA := range namesA {
+		for _, nameB := range namesB {
+			nameScore = max(nameScore, payeeSimilarity(nameA, nameB))
+		}
+	}
+
+	dateScore := 1 - float64(dayDiff)/float64(dayWindow+1)
+	score := 0.7*nameScore + 0.3*dateScore
+
+	reasons := []string{
+		fmt.Sprintf("payee similarity %.0f%%", nameScore*100),
+		fmt.Sprintf("%d day(s) apart", dayDiff),
+	}
+	switch {
+	case a.ImportID != "" && b.ImportID != "":
+		score *= importedPairPenalty
+		reasons = append(reasons, "both imported separately (check it isn't two genuine charges)")
+	case a.ImportID != "" || b.ImportID != "":
+		reasons = append(reasons, "one imported, one entered manually and not matched")
+	}
+
+	return score, reasons
+}
+
+// keeperRank orders transactions by how strongly they should be kept:
+// reconciled, then imported, then cleared, then approved, then categorized
+func keeperRank(tx ynab.Transaction) int {
+	rank := 0
+	if tx.Cleared == "reconciled" {
+		rank += 16
+	}
+	if tx.ImportID != "" {
+		rank += 8
+	}
+	if tx.Cleared == "cleared" {
+		rank += 4
+	}
+	if tx.Approved {
+		rank += 2
+	}
+	if tx.CategoryID != "" {
+		rank++
+	}
+	return rank
+}
+
+// duplicatePair is two transactions in a bucket that score as likely duplicates
+type duplicatePair struct {
+	a, b    int
+	score   float64
+	reasons []string
+}
+
+// findDuplicateGroups finds candidate duplicate groups among transactions.
+// Candidates must share an account and exact amount. Pairs scoring at least
+// minSimilarity are grouped best-first, and a transaction only joins a group
+// when it pairs with every member, so a group never spans more than dayWindow
+// days. That keeps a run of recurring charges (e.g. weekly, with a wide
+// window) from being chained into one group.
+func findDuplicateGroups(transactions []ynab.Transaction, dayWindow int, minSimilarity float64) []duplicateGroup {
+	buckets := make(map[string][]ynab.Transaction)
+	for _, tx := range transactions {
+		key := fmt.Sprintf("%s|%d", tx.AccountID, tx.Amount)
+		buckets[key] = append(buckets[key], tx)
+	}
+
+	groups := make([]duplicateGroup, 0)
+	for _, bucket := range buckets {
+		if len(bucket) < 2 {
+			continue
+		}
+
+		pairs := make([]duplicatePair, 0)
+		linked := make(map[[2]int]bool)
+		for i := 0; i < len(bucket); i++ {
+			for j := i + 1; j < len(bucket); j++ {
+				score, why := duplicateScore(bucket[i], bucket[j], dayWindow)
+				if score < minSimilarity {
+					continue
+				}
+				pairs = append(pairs, duplicatePair{a: i, b: j, score: score, reasons: why})
+				linked[[2]int{i, j}] = true
+				linked[[2]int{j, i}] = true
+			}
+		}
+		sort.SliceStable(pairs, func(x, y int) bool {
+			return pairs[x].score > pairs[y].score
+		})
+
+		groupOf := make(map[int]int)
+		members := make([][]int, 0)
+		bestScore := make(map[int]float64)
+		reasons := make(map[int][]string)
+		// Same-import pairs link regardless of date, so the spread is checked too
+		joins := func(candidate int, group []int) bool {
+			candidateDate, _ := parseDate(bucket[candidate].Date)
+			for _, member := range group {
+				memberDate, _ := parseDate(bucket[member].Date)
+				if !linked[[2]int{candidate, member}] || math.Abs(daysBetween(candidateDate, memberDate)) > float64(dayWindow) {
+					return false
+				}
+			}
+			return true
+		}
+		for _, pair := range pairs {
+			groupA, inA := groupOf[pair.a]
+			groupB, inB := groupOf[pair.b]
+			switch {
+			case !inA && !inB:
+				groupOf[pair.a] = len(members)
+				groupOf[pair.b] = len(members)
+				members = append(members, []int{pair.a, pair.b})
+			case inA && !inB && joins(pair.b, members[groupA]):
+				groupOf[pair.b] = groupA
+				members[groupA] = append(members[groupA], pair.b)
+			case inB && !inA && joins(pair.a, members[groupB]):
+				groupOf[pair.a] = groupB
+				members[groupB] = append(members[groupB], pair.a)
+			default:
+				continue
+			}
+			for _, k := range []int{pair.a, pair.b} {
+				if pair.score > bestScore[k] {
+					bestScore[k] = pair.score
+					reasons[k] = pair.reasons
+				}
+			}
+		}
+
+		for _, indexes := range members {
+			sort.Slice(indexes, func(x, y int) bool {
+				a, b := bucket[indexes[x]], bucket[indexes[y]]
+				if keeperRank(a) != keeperRank(b) {
+					return keeperRank(a) > keeperRank(b)
+				}
+				return a.Date < b.Date
+			})
+
+			keep := bucket[indexes[0]]
+			group := duplicateGroup{
+				Amount:  ynab.MilliunitsToFloat(keep.Amount),
+				Keep:    newTransactionSummary(keep),
+				Extras:  make([]transactionSummary, 0, len(indexes)-1),
+				Reasons: reasons[indexes[0]],
+			}
+			for _, index := range indexes {
+				group.Similarity = max(group.Similarity, roundTo(bestScore[index], 2))
+			}
+			for _, index := range indexes[1:] {
+				group.Extras = append(group.Extras, newTransactionSummary(bucket[index]))
+				group.extras = append(group.extras, bucket[index])
+			}
+			groups = append(groups, group)
+		}
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Similarity != groups[j].Similarity {
+			return groups[i].Similarity > groups[j].Similarity
+		}
+		return groups[i].Keep.Date > groups[j].Keep.Date
+	})
+
+	return groups
+}
+
+// NewFindDuplicateTransactionsTool creates the find_duplicate_transactions tool
+func NewFindDuplicateTransactionsTool(client *ynab.Client) ToolDefinition {
+	tool := mcp.Tool{
+		Name:        "find_duplicate_transactions",
+		Description: "Find likely duplicate transactions (e.g., an imported transaction plus a manually entered one that YNAB did not match). Looks for equal amounts in the same account within a day window and similar payee names, ignoring pairs YNAB already matched. Groups candidates with a similarity score and can optionally flag or delete the extras after a preview (confirm=true).",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"budget_id": map[string]interface{}{
+					"type":        "string",
+					"description": "The ID of the budget",
+				},
+				"since_date": map[string]interface{}{
+					"type":        "string",
+					"description": "Start date in YYYY-MM-DD format",
+				},
+				"until_date": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional: end date in YYYY-MM-DD format (defaults to today)",
+				},
+				"account_id": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional: only scan this account",
+				},
+				"day_window": map[string]interface{}{
+					"type":        "number",
+					"description": fmt.Sprintf("Optional: maximum days between duplicates (0-%d, default %d)", maxDuplicateDayWindow, defaultDuplicateDayWindow),
+					"minimum":     0,
+					"maximum":     maxDuplicateDayWindow,
+				},
+				"min_similarity": map[string]interface{}{
+					"type":        "number",
+					"description": fmt.Sprintf("Optional: minimum similarity score to report (0-1, default %.1f)", defaultDuplicateMinSimilarity),
+					"minimum":     0,
+					"maximum":     1,
+				},
+				"action": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional: what to do with the extras in each group: 'none' (report only, default), 'flag' or 'delete'. Both require confirm=true; reconciled transactions are never deleted.",
+					"enum":        []string{duplicateActionNone, duplicateActionFlag, duplicateActionDelete},
+				},
+				"flag_color": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional: flag color used with action='flag' (default red)",
+					"enum":        []string{"red", "orange", "yellow", "green", "blue", "purple"},
+				},
+				"confirm": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Optional: with action='flag' or 'delete', set to true to actually flag or delete the extras; otherwise they are only listed (default false)",
+				},
+			},
+			Required: []string{"budget_id", "since_date"},
+		},
+	}
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		budgetID, ok := args["budget_id"].(string)
+		if !ok || budgetID == "" {
+			return mcp.NewToolResultError("budget_id is required"), nil
+		}
+
+		sinceDate, ok := args["since_date"].(string)
+		if !ok || sinceDate == "" {
+			return mcp.NewToolResultError("since_date is required (YYYY-MM-DD format)"), nil
+		}
+
+		untilDate := time.Now().Format("2006-01-02")
+		if untilArg, ok := args["until_date"].(string); ok && untilArg != "" {
+			untilDate = untilArg
+		}
+
+		// Validate date range
+		if err := validateDateRange(sinceDate, untilDate); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		dayWindow := defaultDuplicateDayWindow
+		if windowFloat, ok := args["day_window"].(float64); ok {
+			dayWindow = int(windowFloat)
+			if dayWindow < 0 || dayWindow > maxDuplicateDayWindow {
+				return mcp.NewToolResultError(fmt.Sprintf("day_window must be between 0 and %d", maxDuplicateDayWindow)), nil
+			}
+		}
+
+		minSimilarity := defaultDuplicateMinSimilarity
+		if similarityFloat, ok := args["min_similarity"].(float64); ok {
+			if similarityFloat < 0 || similarityFloat > 1 {
+				return mcp.NewToolResultError("min_similarity must be between 0 and 1"), nil
+			}
+			minSimilarity = similarityFloat
+		}
+
+		action := duplicateActionNone
+		if actionArg, ok := args["action"].(string); ok && actionArg != "" {
+			switch actionArg {
+			case duplicateActionNone, duplicateActionFlag, duplicateActionDelete:
+				action = actionArg
+			default:
+				return mcp.NewToolResultError(fmt.Sprintf("Invalid action: %s", actionArg)), nil
+			}
+		}
+
+		confirm, _ := args["confirm"].(bool)
+
+		flagColor := "red"
+		if colorArg, ok := args["flag_color"].(string); ok && colorArg != "" {
+			flagColor = colorArg
+		}
+
+		// Fetch transactions, reaching back far enough to catch duplicates of
+		// transactions just inside the start of the range
+		sinceTime, _ := parseDate(sinceDate)
+		query := &ynab.TransactionQuery{
+			SinceDate: sinceTime.AddDate(0, 0, -dayWindow).Format("2006-01-02"),
+		}
+
+		var transactions []ynab.Transaction
+		var err error
+
+		if accountID, ok := args["account_id"].(string); ok && accountID != "" {
+			transactions, err = client.ListAccountTransactions(budgetID, accountID, query)
+		} else {
+			transactions, err = client.ListTransactions(budgetID, query)
+		}
+
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch transactions: %v", err)), nil
+		}
+
+		candidates := make([]ynab.Transaction, 0, len(transactions))
+		for _, tx := range transactions {
+			if !isAnalyzable(tx) || tx.Date > untilDate {
+				continue
+			}
+			candidates = append(candidates, tx)
+		}
+
+		groups := findDuplicateGroups(candidates, dayWindow, minSimilarity)
+
+		// Only report groups with at least one transaction inside the range
+		inRange := make([]duplicateGroup, 0, len(groups))
+		for _, group := range groups {
+			if group.Keep.Date >= sinceDate {
+				inRange = append(inRange, group)
+				continue
+			}
+			for _, extra := range group.Extras {
+				if extra.Date >= sinceDate {
+					inRange = append(inRange, group)
+					break
+				}
+			}
+		}
+		groups = inRange
+
+		extraCount := 0
+		for _, group := range groups {
+			extraCount += len(group.Extras)
+		}
+
+		actionResults := map[string]interface{}{
+			"action": action,
+		}
+
+		switch action {
+		case duplicateActionFlag:
+			plans := make([]plannedUpdate, 0, extraCount)
+			for _, group := range groups {
+				for _, tx := range group.extras {
+					if plan, changed := planBulkChange(tx, bulkChange{FlagColor: flagColor}, nil); changed {
+						plans = append(plans, plan)
+					}
+				}
+			}
+			actionResults["flag_color"] = flagColor
+			if !confirm {
+				ids := make([]string, 0, len(plans))
+				for _, plan := range plans {
+					ids = append(ids, plan.Transaction.ID)
+				}
+				actionResults["would_flag"] = ids
+				actionResults["note"] = "No changes were made. Review the groups, then call again with confirm=true to flag these transactions."
+				break
+			}
+			if len(plans) > 0 {
+				if _, err := applyPlannedUpdates(client, budgetID, plans, newProgressReporter(ctx, request)); err != nil {
+					return mcp.NewToolResultError(fmt.Sprintf("Failed to flag duplicates: %v", err)), nil
+				}
+			}
+			actionResults["flagged"] = len(plans)
+
+		case duplicateActionDelete:
+			targets := make([]ynab.Transaction, 0, extraCount)
+			skipped := make([]string, 0)
+			for _, group := range groups {
+				for _, tx := range group.extras {
+					if tx.Cleared == "reconciled" {
+						skipped = append(skipped, tx.ID)
+						continue
+					}
+					targets = append(targets, tx)
+				}
+			}
+			if len(skipped) > 0 {
+				actionResults["skipped_reconciled"] = skipped
+			}
+			if !confirm {
+				ids := make([]string, 0, len(targets))
+				for _, tx := range targets {
+					ids = append(ids, tx.ID)
+				}
+				actionResults["would_delete"] = ids
+				actionResults["note"] = "No changes were made. Review the groups, then call again with confirm=true to delete these transactions."
+				break
+			}
+
+			progress := newProgressReporter(ctx, request)
+			deleted := make([]string, 0, len(targets))
+			for _, tx := range targets {
+				if _, err := client.DeleteTransaction(budgetID, tx.ID); err != nil {
+					return mcp.NewToolResultError(fmt.Sprintf("Failed to delete transaction %s after deleting %d of %d duplicates (deleted: %s): %v",
+						tx.ID, len(deleted), len(targets), displayOrNone(strings.Join(deleted, ", ")), err)), nil
+				}
+				deleted = append(deleted, tx.ID)
+				progress(float64(len(deleted)), float64(len(targets)), fmt.Sprintf("Deleted %d of %d duplicates", len(deleted), len(targets)))
+			}
+			actionResults["deleted"] = deleted
+		}
+
+		// Build result
+		result := map[string]interface{}{
+			"groups":          groups,
+			"group_count":     len(groups),
+			"duplicate_count": extraCount,
+			"day_window":      dayWindow,
+			"min_similarity":  minSimilarity,
+			"action_result":   actionResults,
+			"date_range": map[string]string{
+				"since": sinceDate,
+				"until": untilDate,
+			},
+		}
+
+		jsonResult, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to format result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonResult)), nil
+	}
+
+	return ToolDefinition{Tool: tool, Handler: handler}
+}
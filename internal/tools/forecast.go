@@ -0,0 +1,348 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jeff-french/ynab-mcp-server/internal/ynab"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	defaultForecastDays = 30
+	maxForecastDays     = 365
+)
+
+// scheduledMonthSteps maps month-based scheduled frequencies to their step in months
+var scheduledMonthSteps = map[string]int{
+	"monthly":         1,
+	"everyOtherMonth": 2,
+	"every3Months":    3,
+	"every4Months":    4,
+	"twiceAYear":      6,
+	"yearly":          12,
+	"everyOtherYear":  24,
+}
+
+// scheduledDaySteps maps day-based scheduled frequencies to their step in days
+var scheduledDaySteps = map[string]int{
+	"daily":          1,
+	"weekly":         7,
+	"everyOtherWeek": 14,
+	"every4Weeks":    28,
+}
+
+// addMonthsClamped adds months to t and sets the day of month, clamping to
+// the last day of the resulting month (Jan 31 + 1 month = Feb 28/29)
+func addMonthsClamped(t time.Time, months, day int) time.Time {
+	firstOfMonth := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, months, 0)
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(firstOfMonth.Year(), firstOfMonth.Month(), day, 0, 0, 0, 0, time.UTC)
+}
+
+// scheduledOccurrences expands a scheduled transaction into the dates it will
+// occur between from and until (inclusive), starting at its next date.
+// Occurrences already due before from haven't been entered yet, so they are
+// counted on from.
+func scheduledOccurrences(st ynab.ScheduledTransaction, from, until time.Time) ([]time.Time, error) {
+	next, err := parseDate(st.DateNext)
+	if err != nil {
+		return nil, fmt.Errorf("scheduled transaction %s: %w", st.ID, err)
+	}
+
+	// Schedules step forward from the next date, which may have been moved
+	// away from the first date. A next date clamped to the end of a short
+	// month keeps the first date's later day so following months don't drift.
+	anchorDay := next.Day()
+	if first, err := parseDate(st.DateFirst); err == nil && first.Day() > anchorDay && next.AddDate(0, 0, 1).Day() == 1 {
+		anchorDay = first.Day()
+	}
+
+	occurrences := make([]time.Time, 0)
+	add := func(date time.Time) {
+		if date.Before(from) {
+			date = from
+		}
+		if !date.After(until) {
+			occurrences = append(occurrences, date)
+		}
+	}
+
+	switch {
+	case st.Frequency == "never":
+		add(next)
+
+	case st.Frequency == "twiceAMonth":
+		// Twice a month runs on the anchor day and 15 days after (or before)
+		firstDay := anchorDay
+		if firstDay > 15 {
+			firstDay -= 15
+		}
+		add(next)
+		for month := 0; ; month++ {
+			base := addMonthsClamped(next, month, 1)
+			if base.After(until) {
+				break
+			}
+			for _, day := range []int{firstDay, firstDay + 15} {
+				date := addMonthsClamped(base, 0, day)
+				if date.After(next) {
+					add(date)
+				}
+			}
+		}
+
+	case scheduledDaySteps[st.Frequency] > 0:
+		step := scheduledDaySteps[st.Frequency]
+		for date := next; !date.After(until); date = date.AddDate(0, 0, step) {
+			add(date)
+		}
+
+	case scheduledMonthSteps[st.Frequency] > 0:
+		step := scheduledMonthSteps[st.Frequency]
+		add(next)
+		for i := 1; ; i++ {
+			date := addMonthsClamped(next, i*step, anchorDay)
+			if date.After(until) {
+				break
+			}
+			add(date)
+		}
+
+	default:
+		return nil, fmt.Errorf("scheduled transaction %s: unsupported frequency %q", st.ID, st.Frequency)
+	}
+
+	return occurrences, nil
+}
+
+// forecastEvent is a projected scheduled transaction hitting an account
+type forecastEvent struct {
+	Date      string  `json:"date"`
+	PayeeName string  `json:"payee_name"`
+	Amount    float64 `json:"amount"`
+	Transfer  bool    `json:"transfer,omitempty"`
+
+	milliunits int64
+}
+
+// forecastPoint is an account's projected end-of-day balance
+type forecastPoint struct {
+	Date    string  `json:"date"`
+	Balance float64 `json:"balance"`
+}
+
+// accountForecast is the projected balance path of one account
+type accountForecast struct {
+	AccountID         string          `json:"account_id"`
+	AccountName       string          `json:"account_name"`
+	AccountType       string          `json:"account_type"`
+	OnBudget          bool            `json:"on_budget"`
+	StartingBalance   float64         `json:"starting_balance"`
+	EndingBalance     float64         `json:"ending_balance"`
+	LowestBalance     float64         `json:"lowest_balance"`
+	LowestBalanceDate string          `json:"lowest_balance_date"`
+	FirstNegativeDate string          `json:"first_negative_date,omitempty"`
+	Events            []forecastEvent `json:"events"`
+	Daily             []forecastPoint `json:"daily,omitempty"`
+}
+
+// NewForecastCashFlowTool creates the forecast_cash_flow tool
+func NewForecastCashFlowTool(client *ynab.Client) ToolDefinition {
+	tool := mcp.Tool{
+		Name:        "forecast_cash_flow",
+		Description: "Project each account's balance day by day for the next N days, starting from the current balance and applying scheduled transactions (including transfers between accounts). Reports the lowest projected balance per account and the date any asset account goes negative. Useful for timing credit card payments against paydays.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"budget_id": map[string]interface{}{
+					"type":        "string",
+					"description": "The ID of the budget",
+				},
+				"days": map[string]interface{}{
+					"type":        "number",
+					"description": fmt.Sprintf("Optional: number of days to project (1-%d, default %d)", maxForecastDays, defaultForecastDays),
+					"minimum":     1,
+					"maximum":     maxForecastDays,
+				},
+				"account_id": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional: only report this account (transfers from other accounts are still applied)",
+				},
+				"include_daily": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Optional: include the projected balance for every day (default false)",
+				},
+			},
+			Required: []string{"budget_id"},
+		},
+	}
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		budgetID, ok := args["budget_id"].(string)
+		if !ok || budgetID == "" {
+			return mcp.NewToolResultError("budget_id is required"), nil
+		}
+
+		days := defaultForecastDays
+		if daysFloat, ok := args["days"].(float64); ok {
+			days = int(daysFloat)
+			if days < 1 || days > maxForecastDays {
+				return mcp.NewToolResultError(fmt.Sprintf("days must be between 1 and %d", maxForecastDays)), nil
+			}
+		}
+
+		accountFilter, _ := args["account_id"].(string)
+		includeDaily, _ := args["include_daily"].(bool)
+
+		accounts, err := client.ListAccounts(budgetID)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch accounts: %v", err)), nil
+		}
+
+		scheduled, err := client.ListScheduledTransactions(budgetID)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch scheduled transactions: %v", err)), nil
+		}
+
+		today, _ := parseDate(time.Now().Format("2006-01-02"))
+		until := today.AddDate(0, 0, days)
+
+		// Expand scheduled transactions into dated events per account
+		events := make(map[string][]forecastEvent)
+		skipped := make([]string, 0)
+		for _, st := range scheduled {
+			if st.Deleted {
+				continue
+			}
+			occurrences, err := scheduledOccurrences(st, today, until)
+			if err != nil {
+				skipped = append(skipped, err.Error())
+				continue
+			}
+			for _, date := range occurrences {
+				dateStr := date.Format("2006-01-02")
+				events[st.AccountID] = append(events[st.AccountID], forecastEvent{
+					Date:       dateStr,
+					PayeeName:  st.PayeeName,
+					Amount:     ynab.MilliunitsToFloat(st.Amount),
+					Transfer:   st.TransferAccountID != "",
+					milliunits: st.Amount,
+				})
+				// The other side of a transfer moves the opposite way
+				if st.TransferAccountID != "" {
+					label := "Transfer from "
+					if st.Amount > 0 {
+						label = "Transfer to "
+					}
+					events[st.TransferAccountID] = append(events[st.TransferAccountID], forecastEvent{
+						Date:       dateStr,
+						PayeeName:  label + st.AccountName,
+						Amount:     ynab.MilliunitsToFloat(-st.Amount),
+						Transfer:   true,
+						milliunits: -st.Amount,
+					})
+				}
+			}
+		}
+
+		forecasts := make([]accountForecast, 0)
+		goingNegative := make([]map[string]string, 0)
+		for _, account := range accounts {
+			if account.Deleted || account.Closed {
+				continue
+			}
+			if accountFilter != "" && account.ID != accountFilter {
+				continue
+			}
+
+			accountEvents := events[account.ID]
+			sort.SliceStable(accountEvents, func(i, j int) bool {
+				return accountEvents[i].Date < accountEvents[j].Date
+			})
+
+			forecast := accountForecast{
+				AccountID:         account.ID,
+				AccountName:       account.Name,
+				AccountType:       account.Type,
+				OnBudget:          account.OnBudget,
+				StartingBalance:   ynab.MilliunitsToFloat(account.Balance),
+				LowestBalance:     ynab.MilliunitsToFloat(account.Balance),
+				LowestBalanceDate: today.Format("2006-01-02"),
+				Events:            accountEvents,
+			}
+			if forecast.Events == nil {
+				forecast.Events = []forecastEvent{}
+			}
+
+			balance := account.Balance
+			lowest := balance
+			next := 0
+			for date := today; !date.After(until); date = date.AddDate(0, 0, 1) {
+				dateStr := date.Format("2006-01-02")
+				for next < len(accountEvents) && accountEvents[next].Date == dateStr {
+					balance += accountEvents[next].milliunits
+					next++
+				}
+				if balance < lowest {
+					lowest = balance
+					forecast.LowestBalance = ynab.MilliunitsToFloat(balance)
+					forecast.LowestBalanceDate = dateStr
+				}
+				// Liability balances are negative by nature, so only assets can "go negative"
+				if balance < 0 && forecast.FirstNegativeDate == "" && !isLiabilityAccount(account.Type) {
+					forecast.FirstNegativeDate = dateStr
+				}
+				if includeDaily {
+					forecast.Daily = append(forecast.Daily, forecastPoint{Date: dateStr, Balance: ynab.MilliunitsToFloat(balance)})
+				}
+			}
+			forecast.EndingBalance = ynab.MilliunitsToFloat(balance)
+
+			if forecast.FirstNegativeDate != "" {
+				goingNegative = append(goingNegative, map[string]string{
+					"account_name": account.Name,
+					"date":         forecast.FirstNegativeDate,
+				})
+			}
+			forecasts = append(forecasts, forecast)
+		}
+
+		if accountFilter != "" && len(forecasts) == 0 {
+			return mcp.NewToolResultError(fmt.Sprintf("account not found or closed: %s", accountFilter)), nil
+		}
+
+		// Build result
+		result := map[string]interface{}{
+			"accounts":                forecasts,
+			"accounts_going_negative": goingNegative,
+			"forecast_period": map[string]string{
+				"since": today.Format("2006-01-02"),
+				"until": until.Format("2006-01-02"),
+			},
+		}
+		if len(skipped) > 0 {
+			result["skipped_scheduled_transactions"] = skipped
+		}
+
+		jsonResult, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to format result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonResult)), nil
+	}
+
+	return ToolDefinition{Tool: tool, Handler: handler}
+}
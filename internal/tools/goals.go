@@ -0,0 +1,237 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/jeff-french/ynab-mcp-server/internal/ynab"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const defaultGoalPaceMonths = 3
+
+// goalTypeNames maps YNAB goal type codes to the names shown in the app
+var goalTypeNames = map[string]string{
+	"TB":   "Target Category Balance",
+	"TBD":  "Target Category Balance by Date",
+	"MF":   "Monthly Funding",
+	"NEED": "Plan Your Spending",
+	"DEBT": "Debt Payoff",
+}
+
+// goalProgress reports where a category's goal stands
+type goalProgress struct {
+	CategoryID          string   `json:"category_id"`
+	CategoryName        string   `json:"category_name"`
+	CategoryGroupName   string   `json:"category_group_name"`
+	GoalType            string   `json:"goal_type"`
+	GoalTypeName        string   `json:"goal_type_name"`
+	Target              float64  `json:"target"`
+	TargetMonth         string   `json:"target_month,omitempty"`
+	PercentComplete     int      `json:"percent_complete"`
+	Available           float64  `json:"available"`
+	NeededThisMonth     float64  `json:"needed_this_month"`
+	RemainingOverall    float64  `json:"remaining_overall"`
+	RequiredMonthly     *float64 `json:"required_monthly"`
+	CurrentPace         float64  `json:"current_monthly_pace"`
+	ProjectedCompletion string   `json:"projected_completion,omitempty"`
+	OnTrack             *bool    `json:"on_track"`
+}
+
+// requiredMonthlyContribution returns what must be assigned each month to
+// reach the goal by its target month, if the goal has a date or a cadence
+func requiredMonthlyContribution(category ynab.Category) *float64 {
+	var required float64
+	switch {
+	case category.GoalMonthsToBudget > 0 && category.GoalTargetMonth != "":
+		required = ynab.MilliunitsToFloat(category.GoalOverallLeft) / float64(category.GoalMonthsToBudget)
+	case category.GoalType == "MF" || category.GoalType == "NEED":
+		// Repeating targets may be weekly or yearly; convert them to a month's worth
+		monthly, ok := goalCadenceMonthly(category)
+		if !ok {
+			monthly = category.GoalTarget
+		}
+		required = ynab.MilliunitsToFloat(monthly)
+	default:
+		return nil
+	}
+	required = roundTo(required, 2)
+	return &required
+}
+
+// projectGoalCompletion returns the month the remaining amount is covered
+// when assigning pace (milliunits) in each month after month
+func projectGoalCompletion(month string, remaining, pace int64) (string, bool) {
+	if remaining <= 0 {
+		return month, true
+	}
+	if pace <= 0 {
+		return "", false
+	}
+	start, err := parseMonth(month)
+	if err != nil {
+		return "", false
+	}
+	months := int(math.Ceil(float64(remaining) / float64(pace)))
+	return getMonthString(start.AddDate(0, months, 0)), true
+}
+
+// NewGetGoalProgressTool creates the get_goal_progress tool
+func NewGetGoalProgressTool(client *ynab.Client) ToolDefinition {
+	tool := mcp.Tool{
+		Name:        "get_goal_progress",
+		Description: "Report progress on every category goal: percent complete, amount still needed this month, the monthly contribution required to hit the target date, the projected completion month at the recent funding pace, and total underfunding across the budget.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"budget_id": map[string]interface{}{
+					"type":        "string",
+					"description": "The ID of the budget",
+				},
+				"month": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional: month in YYYY-MM format (default: current month)",
+				},
+				"pace_months": map[string]interface{}{
+					"type":        "number",
+					"description": fmt.Sprintf("Optional: months of assignments to average for the funding pace (1-12, default %d)", defaultGoalPaceMonths),
+					"minimum":     1,
+					"maximum":     12,
+				},
+			},
+			Required: []string{"budget_id"},
+		},
+	}
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		budgetID, ok := args["budget_id"].(string)
+		if !ok || budgetID == "" {
+			return mcp.NewToolResultError("budget_id is required"), nil
+		}
+
+		month := getCurrentMonth()
+		if monthArg, ok := args["month"].(string); ok && monthArg != "" {
+			month = monthArg
+		}
+		target, err := parseMonth(month)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid month format: %v", err)), nil
+		}
+
+		paceMonths := defaultGoalPaceMonths
+		if paceFloat, ok := args["pace_months"].(float64); ok {
+			paceMonths = int(paceFloat)
+			if paceMonths < 1 || paceMonths > 12 {
+				return mcp.NewToolResultError("pace_months must be between 1 and 12"), nil
+			}
+		}
+
+		groups, err := client.ListCategories(budgetID)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch categories: %v", err)), nil
+		}
+		groupNames := categoryGroupNameIndex(groups)
+
+		// Goal fields are month-specific, and the pace needs recent assignments
+		assigned := make(map[string]int64)
+		var current *ynab.Month
+		for i := paceMonths - 1; i >= 0; i-- {
+			m := getMonthString(target.AddDate(0, -i, 0))
+			budgetMonth, err := client.GetMonth(budgetID, m+"-01")
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch month %s: %v", m, err)), nil
+			}
+			for _, category := range budgetMonth.Categories {
+				assigned[category.ID] += category.Budgeted
+			}
+			current = budgetMonth
+		}
+
+		goals := make([]goalProgress, 0)
+		totalUnderfunded := int64(0)
+		totalRequiredMonthly := 0.0
+		behind := make([]string, 0)
+		for _, category := range current.Categories {
+			if category.Deleted || category.Hidden || category.GoalType == "" {
+				continue
+			}
+
+			typeName := goalTypeNames[category.GoalType]
+			if typeName == "" {
+				typeName = category.GoalType
+			}
+
+			pace := assigned[category.ID] / int64(paceMonths)
+			progress := goalProgress{
+				CategoryID:        category.ID,
+				CategoryName:      category.Name,
+				CategoryGroupName: groupNames[category.ID],
+				GoalType:          category.GoalType,
+				GoalTypeName:      typeName,
+				Target:            ynab.MilliunitsToFloat(category.GoalTarget),
+				TargetMonth:       category.GoalTargetMonth,
+				PercentComplete:   category.GoalPercentageComplete,
+				Available:         ynab.MilliunitsToFloat(category.Balance),
+				NeededThisMonth:   ynab.MilliunitsToFloat(category.GoalUnderFunded),
+				RemainingOverall:  ynab.MilliunitsToFloat(category.GoalOverallLeft),
+				RequiredMonthly:   requiredMonthlyContribution(category),
+				CurrentPace:       ynab.MilliunitsToFloat(pace),
+			}
+
+			if completion, ok := projectGoalCompletion(month, category.GoalOverallLeft, pace); ok {
+				progress.ProjectedCompletion = completion
+			}
+
+			// Only dated goals can be ahead of or behind schedule
+			if category.GoalTargetMonth != "" {
+				onTrack := false
+				if progress.ProjectedCompletion != "" {
+					onTrack = progress.ProjectedCompletion+"-01" <= category.GoalTargetMonth
+				}
+				progress.OnTrack = &onTrack
+				if !onTrack {
+					behind = append(behind, category.Name)
+				}
+			}
+
+			totalUnderfunded += category.GoalUnderFunded
+			if progress.RequiredMonthly != nil {
+				totalRequiredMonthly += *progress.RequiredMonthly
+			}
+			goals = append(goals, progress)
+		}
+
+		// Most underfunded first
+		sort.SliceStable(goals, func(i, j int) bool {
+			return goals[i].NeededThisMonth > goals[j].NeededThisMonth
+		})
+
+		// Build result
+		result := map[string]interface{}{
+			"month":                  month,
+			"goals":                  goals,
+			"count":                  len(goals),
+			"total_underfunded":      ynab.MilliunitsToFloat(totalUnderfunded),
+			"total_required_monthly": roundTo(totalRequiredMonthly, 2),
+			"ready_to_assign":        ynab.MilliunitsToFloat(current.ToBeBudgeted),
+			"behind_schedule":        behind,
+		}
+
+		jsonResult, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to format result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonResult)), nil
+	}
+
+	return ToolDefinition{Tool: tool, Handler: handler}
+}
@@ -0,0 +1,304 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jeff-french/ynab-mcp-server/internal/ynab"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	defaultIncomeMonths = 6
+
+	// Paychecks vary a little with overtime and deductions; more than this is irregular
+	maxRegularIncomeVariation = 0.25
+)
+
+// isReadyToAssignCategory reports whether a category in YNAB's internal group
+// receives income ("Inflow: Ready to Assign", formerly "To be Budgeted")
+func isReadyToAssignCategory(name string) bool {
+	lower := strings.ToLower(name)
+	return strings.HasPrefix(lower, "inflow") || strings.Contains(lower, "ready to assign") || strings.Contains(lower, "to be budgeted")
+}
+
+// monthlyIncome summarizes income and spending for a month
+type monthlyIncome struct {
+	Month       string   `json:"month"`
+	Income      float64  `json:"income"`
+	Expenses    float64  `json:"expenses"`
+	Refunds     float64  `json:"refunds"`
+	NetExpenses float64  `json:"net_expenses"`
+	Savings     float64  `json:"savings"`
+	SavingsRate *float64 `json:"savings_rate"`
+}
+
+// incomeSource describes income received from one payee
+type incomeSource struct {
+	PayeeID           string  `json:"payee_id,omitempty"`
+	PayeeName         string  `json:"payee_name"`
+	Total             float64 `json:"total"`
+	Deposits          int     `json:"deposits"`
+	AverageAmount     float64 `json:"average_amount"`
+	FirstDeposit      string  `json:"first_deposit"`
+	LastDeposit       string  `json:"last_deposit"`
+	Cadence           string  `json:"cadence,omitempty"`
+	Regular           bool    `json:"regular"`
+	NextExpected      string  `json:"next_expected,omitempty"`
+	MonthlyEquivalent float64 `json:"monthly_equivalent"`
+	ShareOfIncome     float64 `json:"share_of_income"`
+}
+
+// analyzeIncomeSource detects a paycheck-like cadence in one payee's deposits
+// (sorted by date). Same-day deposits are combined into one.
+func analyzeIncomeSource(deposits []ynab.Transaction, numMonths int) incomeSource {
+	source := incomeSource{
+		PayeeID:      deposits[0].PayeeID,
+		PayeeName:    deposits[0].PayeeName,
+		FirstDeposit: deposits[0].Date,
+		LastDeposit:  deposits[len(deposits)-1].Date,
+	}
+
+	dates := make([]time.Time, 0, len(deposits))
+	amounts := make([]float64, 0, len(deposits))
+	lastDate := ""
+	for _, tx := range deposits {
+		amount := ynab.MilliunitsToFloat(tx.Amount)
+		source.Total += amount
+		if tx.Date == lastDate {
+			amounts[len(amounts)-1] += amount
+			continue
+		}
+		date, err := parseDate(tx.Date)
+		if err != nil {
+			continue
+		}
+		dates = append(dates, date)
+		amounts = append(amounts, amount)
+		lastDate = tx.Date
+	}
+
+	source.Deposits = len(amounts)
+	source.Total = roundTo(source.Total, 2)
+	if source.Deposits > 0 {
+		source.AverageAmount = roundTo(source.Total/float64(source.Deposits), 2)
+	}
+	// Without a cadence, spread the total over the whole report period
+	source.MonthlyEquivalent = roundTo(source.Total/float64(numMonths), 2)
+
+	if len(dates) < 3 {
+		return source
+	}
+
+	intervals := make([]float64, 0, len(dates)-1)
+	for i := 1; i < len(dates); i++ {
+		intervals = append(intervals, daysBetween(dates[i-1], dates[i]))
+	}
+	c, ok := classifyInterval(median(intervals))
+	if !ok {
+		return source
+	}
+
+	regular := 0
+	for _, interval := range intervals {
+		if interval >= c.MinDays*0.8 && interval <= c.MaxDays*1.2 {
+			regular++
+		}
+	}
+
+	source.Cadence = c.Name
+	source.Regular = float64(regular)/float64(len(intervals)) >= minCadenceRegularity &&
+		coefficientOfVariation(amounts) <= maxRegularIncomeVariation
+	if source.Regular {
+		source.NextExpected = c.next(dates[len(dates)-1]).Format("2006-01-02")
+		source.MonthlyEquivalent = roundTo(median(amounts)*c.PerMonth, 2)
+	}
+	return source
+}
+
+// NewGetIncomeReportTool creates the get_income_report tool
+func NewGetIncomeReportTool(client *ynab.Client) ToolDefinition {
+	tool := mcp.Tool{
+		Name:        "get_income_report",
+		Description: "Income vs expense report for the last N months. Income is money assigned to Ready to Assign (plus any extra income categories), broken down by source payee with paycheck cadence detection and irregular income flagged. Refunds in spending categories reduce expenses instead of counting as income. Returns monthly income, expenses and savings rate.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"budget_id": map[string]interface{}{
+					"type":        "string",
+					"description": "The ID of the budget",
+				},
+				"num_months": map[string]interface{}{
+					"type":        "number",
+					"description": fmt.Sprintf("Optional: number of months including current (at least 1, default %d)", defaultIncomeMonths),
+					"minimum":     1,
+				},
+				"income_category_ids": map[string]interface{}{
+					"type":        "array",
+					"description": "Optional: additional category IDs whose inflows count as income",
+					"items": map[string]interface{}{
+						"type": "string",
+					},
+				},
+			},
+			Required: []string{"budget_id"},
+		},
+	}
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		budgetID, ok := args["budget_id"].(string)
+		if !ok || budgetID == "" {
+			return mcp.NewToolResultError("budget_id is required"), nil
+		}
+
+		numMonths := defaultIncomeMonths
+		if monthsFloat, ok := args["num_months"].(float64); ok {
+			numMonths = int(monthsFloat)
+			if numMonths < 1 {
+				return mcp.NewToolResultError("num_months must be at least 1"), nil
+			}
+		}
+
+		categoryGroups, err := client.ListCategories(budgetID)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch categories: %v", err)), nil
+		}
+		incomeCategories := make(map[string]bool)
+		for _, group := range categoryGroups {
+			if group.Name != internalCategoryGroupName {
+				continue
+			}
+			for _, category := range group.Categories {
+				if isReadyToAssignCategory(category.Name) {
+					incomeCategories[category.ID] = true
+				}
+			}
+		}
+		for _, id := range stringSliceArg(args, "income_category_ids") {
+			incomeCategories[id] = true
+		}
+
+		months := getLastNMonths(numMonths)
+		onBudget, err := onBudgetAccounts(client, budgetID, transferHandlingOffBudget)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch accounts: %v", err)), nil
+		}
+
+		byMonth := make(map[string]*monthlyIncome, len(months))
+		for _, month := range months {
+			byMonth[month] = &monthlyIncome{Month: month}
+		}
+
+		depositsByPayee := make(map[string][]ynab.Transaction)
+		filter := &transactionFilter{SinceDate: months[0] + "-01"}
+		_, err = streamAggregationLines(client, budgetID, filter, onBudget, transferHandlingOffBudget, newProgressReporter(ctx, request), func(tx ynab.Transaction) {
+			// Tracking accounts don't affect the budget's income or spending
+			if !onBudget[tx.AccountID] {
+				return
+			}
+			txDate, err := parseDate(tx.Date)
+			if err != nil {
+				return
+			}
+			summary, exists := byMonth[getMonthString(txDate)]
+			if !exists {
+				return
+			}
+
+			amount := ynab.MilliunitsToFloat(tx.Amount)
+			switch {
+			case incomeCategories[tx.CategoryID]:
+				summary.Income += amount
+				if tx.Amount > 0 {
+					key := tx.PayeeID
+					if key == "" {
+						key = normalizePayeeName(tx.PayeeName)
+					}
+					depositsByPayee[key] = append(depositsByPayee[key], tx)
+				}
+			case amount < 0:
+				summary.Expenses += -amount
+			default:
+				summary.Refunds += amount
+			}
+		})
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		monthly := make([]monthlyIncome, 0, len(months))
+		totalIncome := 0.0
+		totalNetExpenses := 0.0
+		for _, month := range months {
+			summary := byMonth[month]
+			summary.Income = roundTo(summary.Income, 2)
+			summary.Expenses = roundTo(summary.Expenses, 2)
+			summary.Refunds = roundTo(summary.Refunds, 2)
+			summary.NetExpenses = roundTo(summary.Expenses-summary.Refunds, 2)
+			summary.Savings = roundTo(summary.Income-summary.NetExpenses, 2)
+			if summary.Income > 0 {
+				rate := roundTo(summary.Savings/summary.Income*100, 1)
+				summary.SavingsRate = &rate
+			}
+			totalIncome += summary.Income
+			totalNetExpenses += summary.NetExpenses
+			monthly = append(monthly, *summary)
+		}
+
+		sources := make([]incomeSource, 0, len(depositsByPayee))
+		irregularTotal := 0.0
+		for _, deposits := range depositsByPayee {
+			sort.SliceStable(deposits, func(i, j int) bool {
+				return deposits[i].Date < deposits[j].Date
+			})
+			source := analyzeIncomeSource(deposits, numMonths)
+			if totalIncome > 0 {
+				source.ShareOfIncome = roundTo(source.Total/totalIncome*100, 1)
+			}
+			if !source.Regular {
+				irregularTotal += source.Total
+			}
+			sources = append(sources, source)
+		}
+		sort.Slice(sources, func(i, j int) bool {
+			return sources[i].Total > sources[j].Total
+		})
+
+		// Build result
+		result := map[string]interface{}{
+			"months":         monthly,
+			"income_sources": sources,
+			"totals": map[string]float64{
+				"income":       roundTo(totalIncome, 2),
+				"net_expenses": roundTo(totalNetExpenses, 2),
+				"savings":      roundTo(totalIncome-totalNetExpenses, 2),
+			},
+			"irregular_income": roundTo(irregularTotal, 2),
+		}
+		if totalIncome > 0 {
+			result["savings_rate"] = roundTo((totalIncome-totalNetExpenses)/totalIncome*100, 1)
+			result["irregular_income_share"] = roundTo(irregularTotal/totalIncome*100, 1)
+		}
+		if months[len(months)-1] == getCurrentMonth() {
+			result["note"] = "The current month is incomplete; its figures are month to date."
+		}
+
+		jsonResult, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to format result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonResult)), nil
+	}
+
+	return ToolDefinition{Tool: tool, Handler: handler}
+}
@@ -0,0 +1,201 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/jeff-french/ynab-mcp-server/internal/ynab"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const defaultNetWorthMonths = 12
+
+// netWorthMonth is the reconstructed net worth at the end of a month
+type netWorthMonth struct {
+	Month           string             `json:"month"`
+	Assets          float64            `json:"assets"`
+	Liabilities     float64            `json:"liabilities"`
+	NetWorth        float64            `json:"net_worth"`
+	Change          float64            `json:"change"`
+	ChangePercent   *float64           `json:"change_percent"`
+	AccountBalances map[string]float64 `json:"account_balances,omitempty"`
+}
+
+// reconstructMonthEndBalances walks back from current balances to produce
+// each account's balance at the end of every month. activity holds each
+// account's net transaction amount per month (month → account → milliunits)
+// for every month from months[0] on; a balance at the end of a month is the
+// current balance minus the activity of every later month. Future-dated
+// transactions must be left out of activity since they are not in today's balance.
+func reconstructMonthEndBalances(accounts []ynab.Account, activity map[string]map[string]int64, months []string) map[string]map[string]int64 {
+	running := make(map[string]int64, len(accounts))
+	for _, account := range accounts {
+		running[account.ID] = account.Balance
+	}
+
+	balances := make(map[string]map[string]int64, len(months))
+	for i := len(months) - 1; i >= 0; i-- {
+		snapshot := make(map[string]int64, len(running))
+		for id, balance := range running {
+			snapshot[id] = balance
+		}
+		balances[months[i]] = snapshot
+
+		for id, amount := range activity[months[i]] {
+			if _, tracked := running[id]; tracked {
+				running[id] -= amount
+			}
+		}
+	}
+
+	return balances
+}
+
+// NewGetNetWorthHistoryTool creates the get_net_worth_history tool
+func NewGetNetWorthHistoryTool(client *ynab.Client) ToolDefinition {
+	tool := mcp.Tool{
+		Name:        "get_net_worth_history",
+		Description: "Reconstruct month-end balances for every account (on- and off-budget) by walking transactions backwards from today's balances. Returns a monthly series of assets, liabilities and net worth with month-over-month change.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"budget_id": map[string]interface{}{
+					"type":        "string",
+					"description": "The ID of the budget",
+				},
+				"num_months": map[string]interface{}{
+					"type":        "number",
+					"description": fmt.Sprintf("Optional: number of months including current (at least 1, default %d)", defaultNetWorthMonths),
+					"minimum":     1,
+				},
+				"include_accounts": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Optional: include each account's month-end balance (default false)",
+				},
+			},
+			Required: []string{"budget_id"},
+		},
+	}
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		budgetID, ok := args["budget_id"].(string)
+		if !ok || budgetID == "" {
+			return mcp.NewToolResultError("budget_id is required"), nil
+		}
+
+		numMonths := defaultNetWorthMonths
+		if monthsFloat, ok := args["num_months"].(float64); ok {
+			numMonths = int(monthsFloat)
+			if numMonths < 1 {
+				return mcp.NewToolResultError("num_months must be at least 1"), nil
+			}
+		}
+
+		includeAccounts, _ := args["include_accounts"].(bool)
+
+		accounts, err := client.ListAccounts(budgetID)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch accounts: %v", err)), nil
+		}
+
+		// Closed accounts still count: they may have carried balances in earlier months
+		active := make([]ynab.Account, 0, len(accounts))
+		for _, account := range accounts {
+			if !account.Deleted {
+				active = append(active, account)
+			}
+		}
+
+		months := getLastNMonths(numMonths)
+
+		// Everything after the start of the first month is needed to walk back to it
+		today := time.Now().Format("2006-01-02")
+		activity := make(map[string]map[string]int64, len(months))
+		filter := &transactionFilter{SinceDate: months[0] + "-01"}
+		_, err = streamAggregationLines(client, budgetID, filter, nil, transferHandlingAll, newProgressReporter(ctx, request), func(line ynab.Transaction) {
+			if line.Date > today {
+				return
+			}
+			txDate, err := parseDate(line.Date)
+			if err != nil {
+				return
+			}
+			month := getMonthString(txDate)
+			if activity[month] == nil {
+				activity[month] = make(map[string]int64)
+			}
+			activity[month][line.AccountID] += line.Amount
+		})
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		balances := reconstructMonthEndBalances(active, activity, months)
+
+		history := make([]netWorthMonth, 0, len(months))
+		for i, month := range months {
+			entry := netWorthMonth{Month: month}
+			assets := int64(0)
+			liabilities := int64(0)
+			if includeAccounts {
+				entry.AccountBalances = make(map[string]float64, len(active))
+			}
+
+			for _, account := range active {
+				balance := balances[month][account.ID]
+				if isLiabilityAccount(account.Type) {
+					liabilities += balance
+				} else {
+					assets += balance
+				}
+				if includeAccounts {
+					entry.AccountBalances[account.Name] = ynab.MilliunitsToFloat(balance)
+				}
+			}
+
+			entry.Assets = ynab.MilliunitsToFloat(assets)
+			// Liabilities are stored negative in YNAB; report them as a positive amount owed
+			entry.Liabilities = ynab.MilliunitsToFloat(-liabilities)
+			entry.NetWorth = ynab.MilliunitsToFloat(assets + liabilities)
+
+			if i > 0 {
+				previous := history[i-1].NetWorth
+				entry.Change = roundTo(entry.NetWorth-previous, 2)
+				if previous != 0 {
+					percent := roundTo(entry.Change/math.Abs(previous)*100, 1)
+					entry.ChangePercent = &percent
+				}
+			}
+
+			history = append(history, entry)
+		}
+
+		first := history[0]
+		last := history[len(history)-1]
+
+		// Build result
+		result := map[string]interface{}{
+			"months":            history,
+			"current_net_worth": last.NetWorth,
+			"total_change":      roundTo(last.NetWorth-first.NetWorth, 2),
+			"accounts_included": len(active),
+		}
+
+		jsonResult, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to format result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonResult)), nil
+	}
+
+	return ToolDefinition{Tool: tool, Handler: handler}
+}
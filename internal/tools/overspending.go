@@ -0,0 +1,341 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jeff-french/ynab-mcp-server/internal/ynab"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// readyToAssignSource is the source name used for moves out of Ready to Assign
+const readyToAssignSource = "Ready to Assign"
+
+// Cover source priorities, lowest first
+const (
+	coverPriorityNoGoal = iota
+	coverPriorityGoalMet
+	coverPriorityGoalUnderfunded
+)
+
+// overspentCategory is a category with a negative available balance
+type overspentCategory struct {
+	Category ynab.Category
+	Group    string
+	Cash     int64 // overspending funded by cash accounts, in milliunits
+	Credit   int64 // overspending charged to credit accounts, in milliunits
+}
+
+// coverSource is a category (or Ready to Assign) with money to move
+type coverSource struct {
+	CategoryID string // empty for Ready to Assign
+	Name       string
+	Available  int64
+	Priority   int
+}
+
+// coverMove moves money from a source to an overspent category
+type coverMove struct {
+	From   coverSource
+	To     ynab.Category
+	Amount int64
+}
+
+// splitOverspending estimates how much of a category's overspending was
+// charged to credit accounts. Credit spending is assumed to be what pushed
+// the category negative, so it is counted first.
+func splitOverspending(overspent, creditSpending int64) (cash, credit int64) {
+	credit = min(overspent, creditSpending)
+	return overspent - credit, credit
+}
+
+// coverPriority ranks a surplus category as a cover source: categories
+// without goals first, then met goals, then goals still underfunded
+func coverPriority(category ynab.Category) int {
+	switch {
+	case category.GoalType == "":
+		return coverPriorityNoGoal
+	case category.GoalUnderFunded == 0:
+		return coverPriorityGoalMet
+	default:
+		return coverPriorityGoalUnderfunded
+	}
+}
+
+// planCoverMoves covers overspent categories, largest first, from sources in
+// priority order. Sources are consumed in place. Returns the moves and the
+// overspending left uncovered.
+func planCoverMoves(overspent []overspentCategory, sources []*coverSource) ([]coverMove, int64) {
+	moves := make([]coverMove, 0)
+	uncovered := int64(0)
+	for _, target := range overspent {
+		needed := -target.Category.Balance
+		for _, source := range sources {
+			if needed == 0 {
+				break
+			}
+			amount := min(needed, source.Available)
+			if amount <= 0 {
+				continue
+			}
+			moves = append(moves, coverMove{From: *source, To: target.Category, Amount: amount})
+			source.Available -= amount
+			needed -= amount
+		}
+		uncovered += needed
+	}
+	return moves, uncovered
+}
+
+// NewFindOverspendingTool creates the find_overspending tool
+func NewFindOverspendingTool(client *ynab.Client) ToolDefinition {
+	tool := mcp.Tool{
+		Name:        "find_overspending",
+		Description: "Find categories with a negative available balance in a month, split into cash overspending (reduces next month's Ready to Assign) and credit overspending (becomes credit card debt), and propose moves that cover them from Ready to Assign and from categories with money available. Categories without goals are used first, then categories whose goals are already met. With confirm=true the moves are applied by updating the assigned amounts.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"budget_id": map[string]interface{}{
+					"type":        "string",
+					"description": "The ID of the budget",
+				},
+				"month": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional: month in YYYY-MM format (default: current month)",
+				},
+				"use_ready_to_assign": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Optional: cover from Ready to Assign before taking from other categories (default true)",
+				},
+				"allow_underfunded_goals": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Optional: also take from categories whose goals are not yet met, as a last resort (default false)",
+				},
+				"confirm": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Optional: apply the proposed moves (default false, preview only)",
+				},
+			},
+			Required: []string{"budget_id"},
+		},
+	}
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		budgetID, ok := args["budget_id"].(string)
+		if !ok || budgetID == "" {
+			return mcp.NewToolResultError("budget_id is required"), nil
+		}
+
+		month := getCurrentMonth()
+		if monthArg, ok := args["month"].(string); ok && monthArg != "" {
+			month = monthArg
+		}
+		start, err := parseMonth(month)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid month format: %v", err)), nil
+		}
+
+		useReadyToAssign := true
+		if use, ok := args["use_ready_to_assign"].(bool); ok {
+			useReadyToAssign = use
+		}
+		allowUnderfunded, _ := args["allow_underfunded_goals"].(bool)
+		confirm, _ := args["confirm"].(bool)
+
+		budgetMonth, err := client.GetMonth(budgetID, month+"-01")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch month: %v", err)), nil
+		}
+
+		groups, err := client.ListCategories(budgetID)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch categories: %v", err)), nil
+		}
+		groupNames := categoryGroupNameIndex(groups)
+		budgetCategories := make(map[string]bool)
+		for _, group := range groups {
+			if !isBudgetCategoryGroup(group) {
+				continue
+			}
+			for _, category := range group.Categories {
+				budgetCategories[category.ID] = true
+			}
+		}
+
+		overspent := make([]overspentCategory, 0)
+		sources := make([]*coverSource, 0)
+		if useReadyToAssign && budgetMonth.ToBeBudgeted > 0 {
+			sources = append(sources, &coverSource{Name: readyToAssignSource, Available: budgetMonth.ToBeBudgeted, Priority: -1})
+		}
+		for _, category := range budgetMonth.Categories {
+			if category.Deleted || !budgetCategories[category.ID] {
+				continue
+			}
+			switch {
+			case category.Balance < 0:
+				overspent = append(overspent, overspentCategory{Category: category, Group: groupNames[category.ID]})
+			case category.Balance > 0 && !category.Hidden:
+				priority := coverPriority(category)
+				if priority == coverPriorityGoalUnderfunded && !allowUnderfunded {
+					continue
+				}
+				sources = append(sources, &coverSource{
+					CategoryID: category.ID,
+					Name:       category.Name,
+					Available:  category.Balance,
+					Priority:   priority,
+				})
+			}
+		}
+
+		if len(overspent) == 0 {
+			return mcp.NewToolResultText(fmt.Sprintf("No overspent categories in %s.", month)), nil
+		}
+
+		// Credit spending in each overspent category decides how much is credit overspending
+		accounts, err := client.ListAccounts(budgetID)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch accounts: %v", err)), nil
+		}
+		creditAccounts := make(map[string]bool)
+		for _, account := range accounts {
+			if isLiabilityAccount(account.Type) && account.OnBudget {
+				creditAccounts[account.ID] = true
+			}
+		}
+
+		// Reading the month's transactions is the first half of the work when the moves are applied
+		progress := newProgressReporter(ctx, request)
+		readSpan := 100.0
+		if confirm {
+			readSpan = 50
+		}
+		filter := &transactionFilter{
+			SinceDate: month + "-01",
+			UntilDate: start.AddDate(0, 1, -1).Format("2006-01-02"),
+		}
+		creditSpending := make(map[string]int64)
+		_, err = streamAggregationLines(client, budgetID, filter, nil, transferHandlingAll, progressPhase(progress, 0, readSpan), func(line ynab.Transaction) {
+			if creditAccounts[line.AccountID] && line.Amount < 0 {
+				creditSpending[line.CategoryID] += -line.Amount
+			}
+		})
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		totalCash := int64(0)
+		totalCredit := int64(0)
+		for i := range overspent {
+			overspent[i].Cash, overspent[i].Credit = splitOverspending(-overspent[i].Category.Balance, creditSpending[overspent[i].Category.ID])
+			totalCash += overspent[i].Cash
+			totalCredit += overspent[i].Credit
+		}
+
+		// Largest overspending first; largest sources first within each priority
+		sort.SliceStable(overspent, func(i, j int) bool {
+			return overspent[i].Category.Balance < overspent[j].Category.Balance
+		})
+		sort.SliceStable(sources, func(i, j int) bool {
+			if sources[i].Priority != sources[j].Priority {
+				return sources[i].Priority < sources[j].Priority
+			}
+			return sources[i].Available > sources[j].Available
+		})
+
+		moves, uncovered := planCoverMoves(overspent, sources)
+
+		var result strings.Builder
+		result.WriteString(fmt.Sprintf("Overspending in %s: %d categories, %s total\n", month, len(overspent),
+			ynab.FormatCurrency(totalCash+totalCredit)))
+		result.WriteString(fmt.Sprintf("  Cash overspending: %s (reduces next month's Ready to Assign)\n", ynab.FormatCurrency(totalCash)))
+		result.WriteString(fmt.Sprintf("  Credit overspending: %s (becomes credit card debt)\n\n", ynab.FormatCurrency(totalCredit)))
+
+		for _, category := range overspent {
+			result.WriteString(fmt.Sprintf("- %s", category.Category.Name))
+			if category.Group != "" {
+				result.WriteString(fmt.Sprintf(" (%s)", category.Group))
+			}
+			result.WriteString(fmt.Sprintf(": %s available", ynab.FormatCurrency(category.Category.Balance)))
+			switch {
+			case category.Credit == 0:
+				result.WriteString(" [cash]\n")
+			case category.Cash == 0:
+				result.WriteString(" [credit]\n")
+			default:
+				result.WriteString(fmt.Sprintf(" [cash %s, credit %s]\n",
+					ynab.FormatCurrency(category.Cash), ynab.FormatCurrency(category.Credit)))
+			}
+		}
+
+		if len(moves) == 0 {
+			result.WriteString("\nNo categories have money available to cover the overspending.\n")
+			return mcp.NewToolResultText(result.String()), nil
+		}
+
+		result.WriteString("\nProposed moves:\n")
+		for _, move := range moves {
+			result.WriteString(fmt.Sprintf("- %s from %s to %s", ynab.FormatCurrency(move.Amount), move.From.Name, move.To.Name))
+			switch move.From.Priority {
+			case coverPriorityGoalMet:
+				result.WriteString(" (goal already met)")
+			case coverPriorityGoalUnderfunded:
+				result.WriteString(" (⚠️  goal not yet met)")
+			}
+			result.WriteString("\n")
+		}
+		if uncovered > 0 {
+			result.WriteString(fmt.Sprintf("\n⚠️  %s of overspending cannot be covered from available money.\n", ynab.FormatCurrency(uncovered)))
+		}
+		result.WriteString("\n")
+
+		if !confirm {
+			result.WriteString("No changes were made. Call again with confirm=true to apply these moves.\n")
+			return mcp.NewToolResultText(result.String()), nil
+		}
+
+		// Net each category's change so it is updated once
+		budgeted := make(map[string]int64)
+		names := make(map[string]string)
+		for _, category := range budgetMonth.Categories {
+			budgeted[category.ID] = category.Budgeted
+			names[category.ID] = category.Name
+		}
+		changes := make(map[string]int64)
+		order := make([]string, 0)
+		addChange := func(categoryID string, amount int64) {
+			if _, seen := changes[categoryID]; !seen {
+				order = append(order, categoryID)
+			}
+			changes[categoryID] += amount
+		}
+		for _, move := range moves {
+			// Assigning more from Ready to Assign only touches the target
+			if move.From.CategoryID != "" {
+				addChange(move.From.CategoryID, -move.Amount)
+			}
+			addChange(move.To.ID, move.Amount)
+		}
+
+		applyProgress := progressPhase(progress, readSpan, 100-readSpan)
+		for i, categoryID := range order {
+			if _, err := client.UpdateMonthCategory(budgetID, month+"-01", categoryID, budgeted[categoryID]+changes[categoryID]); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Failed to update %s after %d of %d categories were updated: %v",
+					names[categoryID], i, len(order), err)), nil
+			}
+			applyProgress(float64(i+1), float64(len(order)), fmt.Sprintf("Updated %d of %d categories", i+1, len(order)))
+		}
+
+		result.WriteString(fmt.Sprintf("Applied %d move(s), updating %d categories successfully!\n", len(moves), len(order)))
+		return mcp.NewToolResultText(result.String()), nil
+	}
+
+	return ToolDefinition{Tool: tool, Handler: handler}
+}
@@ -0,0 +1,413 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jeff-french/ynab-mcp-server/internal/ynab"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	defaultAggregateLimit = 100
+	maxAggregateLimit     = 1000
+)
+
+// groupingDimensions are the fields aggregate_transactions can group by
+var groupingDimensions = []string{
+	"category", "category_group", "payee", "account",
+	"day", "week", "month", "quarter", "year",
+	"flag", "cleared",
+}
+
+// groupingKey extracts a dimension's ID and display label from a transaction
+type groupingKey func(tx ynab.Transaction) (id, label string)
+
+// categoryKey groups by category, with uncategorized transactions together
+func categoryKey(tx ynab.Transaction) (string, string) {
+	if tx.CategoryID == "" {
+		return "uncategorized", "Uncategorized"
+	}
+	return tx.CategoryID, tx.CategoryName
+}
+
+// payeeKey groups by payee, with payee-less transactions together
+func payeeKey(tx ynab.Transaction) (string, string) {
+	if tx.PayeeID == "" {
+		return "no-payee", "No Payee"
+	}
+	return tx.PayeeID, tx.PayeeName
+}
+
+// monthKey groups by calendar month (YYYY-MM)
+func monthKey(tx ynab.Transaction) (string, string) {
+	txDate, err := parseDate(tx.Date)
+	if err != nil {
+		return "", ""
+	}
+	month := getMonthString(txDate)
+	return month, month
+}
+
+// newGroupingKey returns the key function for a dimension. groupNames maps
+// category IDs to group names and is only needed for category_group.
+func newGroupingKey(dimension string, groupNames map[string]string) (groupingKey, error) {
+	dateKey := func(format func(t time.Time) string) groupingKey {
+		return func(tx ynab.Transaction) (string, string) {
+			txDate, err := parseDate(tx.Date)
+			if err != nil {
+				return "", ""
+			}
+			value := format(txDate)
+			return value, value
+		}
+	}
+
+	switch dimension {
+	case "category":
+		return categoryKey, nil
+	case "category_group":
+		return func(tx ynab.Transaction) (string, string) {
+			name := groupNames[tx.CategoryID]
+			if name == "" {
+				name = "Uncategorized"
+			}
+			return name, name
+		}, nil
+	case "payee":
+		return payeeKey, nil
+	case "account":
+		return func(tx ynab.Transaction) (string, string) {
+			return tx.AccountID, tx.AccountName
+		}, nil
+	case "day":
+		return dateKey(func(t time.Time) string {
+			return t.Format("2006-01-02")
+		}), nil
+	case "week":
+		// Weeks start on Monday and are labelled by their first day
+		return dateKey(func(t time.Time) string {
+			offset := (int(t.Weekday()) + 6) % 7
+			return t.AddDate(0, 0, -offset).Format("2006-01-02")
+		}), nil
+	case "month":
+		return monthKey, nil
+	case "quarter":
+		return dateKey(func(t time.Time) string {
+			return fmt.Sprintf("%04d-Q%d", t.Year(), (int(t.Month())-1)/3+1)
+		}), nil
+	case "year":
+		return dateKey(func(t time.Time) string {
+			return t.Format("2006")
+		}), nil
+	case "flag":
+		return func(tx ynab.Transaction) (string, string) {
+			if tx.FlagColor == "" {
+				return "none", "none"
+			}
+			return tx.FlagColor, tx.FlagColor
+		}, nil
+	case "cleared":
+		return func(tx ynab.Transaction) (string, string) {
+			return tx.Cleared, tx.Cleared
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown group_by dimension %q (expected one of: %s)", dimension, strings.Join(groupingDimensions, ", "))
+	}
+}
+
+// aggregateGroup holds summed amounts for one combination of grouping keys
+type aggregateGroup struct {
+	IDs              []string
+	Labels           []string
+	TotalOutflow     float64
+	TotalInflow      float64
+	Net              float64
+	TransactionCount int
+}
+
+// aggregator sums transactions into groups one at a time, so memory grows
+// with the number of groups rather than the number of transactions
+type aggregator struct {
+	keys   []groupingKey
+	groups map[string]*aggregateGroup
+}
+
+// newAggregator returns an aggregator grouping by every combination of keys
+func newAggregator(keys []groupingKey) *aggregator {
+	return &aggregator{keys: keys, groups: make(map[string]*aggregateGroup)}
+}
+
+// add sums a transaction into its group. Transactions should first go through
+// prepareForAggregation; deleted ones and those with an empty key are skipped.
+func (a *aggregator) add(tx ynab.Transaction) {
+	if tx.Deleted {
+		return
+	}
+
+	ids := make([]string, len(a.keys))
+	labels := make([]string, len(a.keys))
+	for i, key := range a.keys {
+		ids[i], labels[i] = key(tx)
+		if ids[i] == "" {
+			return
+		}
+	}
+
+	composite := strings.Join(ids, "\x1f")
+	group, exists := a.groups[composite]
+	if !exists {
+		group = &aggregateGroup{IDs: ids, Labels: labels}
+		a.groups[composite] = group
+	}
+
+	amount := ynab.MilliunitsToFloat(tx.Amount)
+	if amount < 0 {
+		group.TotalOutflow += -amount // Store as positive
+	} else {
+		group.TotalInflow += amount
+	}
+	group.Net += amount
+	group.TransactionCount++
+}
+
+// aggregateTransactions groups transactions by every combination of the given
+// keys and sums their amounts (see aggregator.add)
+func aggregateTransactions(transactions []ynab.Transaction, keys []groupingKey) map[string]*aggregateGroup {
+	agg := newAggregator(keys)
+	for _, tx := range transactions {
+		agg.add(tx)
+	}
+	return agg.groups
+}
+
+// aggregateRow is one row of the aggregate_transactions table
+type aggregateRow struct {
+	Group            map[string]string `json:"group"`
+	IDs              map[string]string `json:"ids,omitempty"`
+	TotalOutflow     float64           `json:"total_outflow"`
+	TotalInflow      float64           `json:"total_inflow"`
+	Net              float64           `json:"net"`
+	TransactionCount int               `json:"transaction_count"`
+	PercentOfOutflow float64           `json:"percent_of_outflow"`
+}
+
+// NewAggregateTransactionsTool creates the aggregate_transactions pivot tool
+func NewAggregateTransactionsTool(client *ynab.Client) ToolDefinition {
+	properties := transactionFilterProperties()
+	properties["budget_id"] = map[string]interface{}{
+		"type":        "string",
+		"description": "The ID of the budget",
+	}
+	properties["group_by"] = map[string]interface{}{
+		"type":        "array",
+		"description": "Dimensions to group by, in order (e.g. [\"category_group\", \"month\"])",
+		"items": map[string]interface{}{
+			"type": "string",
+			"enum": groupingDimensions,
+		},
+	}
+	properties["sort_by"] = map[string]interface{}{
+		"type":        "string",
+		"description": "Optional: 'outflow' (default), 'inflow', 'net', 'count', or 'group' (group labels, useful for time series)",
+		"enum":        []string{"outflow", "inflow", "net", "count", "group"},
+	}
+	properties["order"] = map[string]interface{}{
+		"type":        "string",
+		"description": "Optional: 'desc' or 'asc' (default desc, or asc when sorting by group)",
+		"enum":        []string{"desc", "asc"},
+	}
+	properties["limit"] = map[string]interface{}{
+		"type":        "number",
+		"description": fmt.Sprintf("Optional: maximum rows to return (1-%d, default %d)", maxAggregateLimit, defaultAggregateLimit),
+		"minimum":     1,
+		"maximum":     maxAggregateLimit,
+	}
+	properties["transfer_handling"] = transferHandlingProperty()
+
+	tool := mcp.Tool{
+		Name:        "aggregate_transactions",
+		Description: "Pivot transactions into a table of outflow, inflow, net and count grouped by any combination of category, category_group, payee, account, day, week, month, quarter, year, flag and cleared status. Accepts the same filters as search_transactions. Split transactions are counted per split line.",
+		InputSchema: mcp.ToolInputSchema{
+			Type:       "object",
+			Properties: properties,
+			Required:   []string{"budget_id", "group_by"},
+		},
+	}
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		budgetID, ok := args["budget_id"].(string)
+		if !ok || budgetID == "" {
+			return mcp.NewToolResultError("budget_id is required"), nil
+		}
+
+		dimensions := stringSliceArg(args, "group_by")
+		if len(dimensions) == 0 {
+			return mcp.NewToolResultError("group_by must list at least one dimension"), nil
+		}
+
+		sortBy := "outflow"
+		if sortArg, ok := args["sort_by"].(string); ok && sortArg != "" {
+			switch sortArg {
+			case "outflow", "inflow", "net", "count", "group":
+				sortBy = sortArg
+			default:
+				return mcp.NewToolResultError("sort_by must be one of: outflow, inflow, net, count, group"), nil
+			}
+		}
+		ascending := sortBy == "group"
+		if order, ok := args["order"].(string); ok && order != "" {
+			if order != "asc" && order != "desc" {
+				return mcp.NewToolResultError("order must be 'asc' or 'desc'"), nil
+			}
+			ascending = order == "asc"
+		}
+
+		limit := defaultAggregateLimit
+		if limitFloat, ok := args["limit"].(float64); ok {
+			limit = int(limitFloat)
+			if limit < 1 || limit > maxAggregateLimit {
+				return mcp.NewToolResultError(fmt.Sprintf("limit must be between 1 and %d", maxAggregateLimit)), nil
+			}
+		}
+
+		transferHandling, err := parseTransferHandling(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		filter, err := parseTransactionFilter(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		var groupNames map[string]string
+		for _, dimension := range dimensions {
+			if dimension == "category_group" {
+				categoryGroups, err := client.ListCategories(budgetID)
+				if err != nil {
+					return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch categories: %v", err)), nil
+				}
+				groupNames = categoryGroupNameIndex(categoryGroups)
+				break
+			}
+		}
+
+		keys := make([]groupingKey, 0, len(dimensions))
+		for _, dimension := range dimensions {
+			key, err := newGroupingKey(dimension, groupNames)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			keys = append(keys, key)
+		}
+
+		onBudget, err := onBudgetAccounts(client, budgetID, transferHandling)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch accounts: %v", err)), nil
+		}
+
+		agg := newAggregator(keys)
+		if _, err := streamAggregationLines(client, budgetID, filter, onBudget, transferHandling, newProgressReporter(ctx, request), agg.add); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		groups := agg.groups
+
+		totalOutflow := 0.0
+		totalInflow := 0.0
+		for _, group := range groups {
+			totalOutflow += group.TotalOutflow
+			totalInflow += group.TotalInflow
+		}
+
+		rows := make([]aggregateRow, 0, len(groups))
+		for _, group := range groups {
+			row := aggregateRow{
+				Group:            make(map[string]string, len(dimensions)),
+				TotalOutflow:     roundTo(group.TotalOutflow, 2),
+				TotalInflow:      roundTo(group.TotalInflow, 2),
+				Net:              roundTo(group.Net, 2),
+				TransactionCount: group.TransactionCount,
+			}
+			for i, dimension := range dimensions {
+				row.Group[dimension] = group.Labels[i]
+				if group.IDs[i] != group.Labels[i] {
+					if row.IDs == nil {
+						row.IDs = make(map[string]string)
+					}
+					row.IDs[dimension] = group.IDs[i]
+				}
+			}
+			if totalOutflow > 0 {
+				row.PercentOfOutflow = roundTo(group.TotalOutflow/totalOutflow*100, 1)
+			}
+			rows = append(rows, row)
+		}
+
+		groupLabel := func(row aggregateRow) string {
+			labels := make([]string, len(dimensions))
+			for i, dimension := range dimensions {
+				labels[i] = row.Group[dimension]
+			}
+			return strings.Join(labels, "\x1f")
+		}
+		less := func(a, b aggregateRow) bool {
+			switch sortBy {
+			case "inflow":
+				return a.TotalInflow < b.TotalInflow
+			case "net":
+				return a.Net < b.Net
+			case "count":
+				return a.TransactionCount < b.TransactionCount
+			case "group":
+				return groupLabel(a) < groupLabel(b)
+			default:
+				return a.TotalOutflow < b.TotalOutflow
+			}
+		}
+		// Order by group first so ties come out in a stable order
+		sort.Slice(rows, func(i, j int) bool {
+			return groupLabel(rows[i]) < groupLabel(rows[j])
+		})
+		sort.SliceStable(rows, func(i, j int) bool {
+			if ascending {
+				return less(rows[i], rows[j])
+			}
+			return less(rows[j], rows[i])
+		})
+
+		totalRows := len(rows)
+		if len(rows) > limit {
+			rows = rows[:limit]
+		}
+
+		// Build result
+		result := map[string]interface{}{
+			"group_by":      dimensions,
+			"rows":          rows,
+			"row_count":     totalRows,
+			"returned":      len(rows),
+			"total_outflow": roundTo(totalOutflow, 2),
+			"total_inflow":  roundTo(totalInflow, 2),
+			"net":           roundTo(totalInflow-totalOutflow, 2),
+		}
+
+		jsonResult, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to format result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonResult)), nil
+	}
+
+	return ToolDefinition{Tool: tool, Handler: handler}
+}
@@ -0,0 +1,334 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/jeff-french/ynab-mcp-server/internal/ynab"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const defaultPlanHistoryMonths = 3
+
+// Bases for a planned amount
+const (
+	planBasisGoal    = "goal"
+	planBasisAverage = "average"
+	planBasisNone    = "none"
+)
+
+// plannedCategory is the proposed assignment for one category
+type plannedCategory struct {
+	CategoryID        string  `json:"category_id"`
+	CategoryName      string  `json:"category_name"`
+	CategoryGroupName string  `json:"category_group_name"`
+	Basis             string  `json:"basis"`
+	CurrentAssigned   float64 `json:"current_assigned"`
+	ProposedAssigned  float64 `json:"proposed_assigned"`
+	Change            float64 `json:"change"`
+	Rationale         string  `json:"rationale"`
+
+	current  int64
+	proposed int64
+}
+
+// goalCadenceMonthly converts a repeating goal's target to a monthly amount
+// using YNAB's goal_cadence codes: 1 monthly, 2 weekly and 13 yearly repeat
+// every goal_cadence_frequency periods; 3-12 repeat every 2-11 months and
+// 14 every 2 years.
+func goalCadenceMonthly(category ynab.Category) (int64, bool) {
+	frequency := float64(max(category.GoalCadenceFrequency, 1))
+	target := float64(category.GoalTarget)
+	var monthly float64
+	switch {
+	case category.GoalCadence == 1:
+		monthly = target / frequency
+	case category.GoalCadence == 2:
+		monthly = target * 52 / 12 / frequency
+	case category.GoalCadence == 13:
+		monthly = target / (12 * frequency)
+	case category.GoalCadence >= 3 && category.GoalCadence <= 12:
+		monthly = target / float64(category.GoalCadence-1)
+	case category.GoalCadence == 14:
+		monthly = target / 24
+	default:
+		return 0, false
+	}
+	return int64(math.Round(monthly)), true
+}
+
+// planCategory proposes an amount for a category from its goal or, without
+// one, its average monthly spending (milliunits, positive) net of the money
+// already carried into the month
+func planCategory(category ynab.Category, averageSpending int64, historyMonths int) (int64, string, string) {
+	if category.GoalType != "" {
+		typeName := goalTypeNames[category.GoalType]
+		if typeName == "" {
+			typeName = category.GoalType
+		}
+		proposed := category.Budgeted + category.GoalUnderFunded
+		rationale := fmt.Sprintf("%s goal of %s", typeName, ynab.FormatCurrency(category.GoalTarget))
+		if monthly, ok := goalCadenceMonthly(category); ok {
+			rationale += fmt.Sprintf(" (about %s per month)", ynab.FormatCurrency(monthly))
+		}
+		if len(category.GoalTargetMonth) >= 7 {
+			rationale += fmt.Sprintf(" due %s", category.GoalTargetMonth[:7])
+		}
+		if category.GoalUnderFunded > 0 {
+			rationale += fmt.Sprintf(" needs %s more this month", ynab.FormatCurrency(category.GoalUnderFunded))
+		} else {
+			rationale += " is already funded this month"
+		}
+		return proposed, planBasisGoal, rationale
+	}
+
+	if averageSpending <= 0 {
+		return category.Budgeted, planBasisNone, fmt.Sprintf("No goal and no spending in the last %d months; kept as is", historyMonths)
+	}
+
+	// Round to whole currency units; cents don't help a plan
+	average := int64(math.Ceil(float64(averageSpending)/1000)) * 1000
+	carryover := category.Balance - category.Budgeted - category.Activity
+	proposed := max(average-max(carryover, 0), 0)
+	rationale := fmt.Sprintf("Average spending of %s per month over the last %d months", ynab.FormatCurrency(average), historyMonths)
+	if carryover > 0 {
+		rationale += fmt.Sprintf(", less %s carried over", ynab.FormatCurrency(carryover))
+	}
+	return proposed, planBasisAverage, rationale
+}
+
+// fitPlan scales proposals down to the money available. Average-based
+// amounts are cut first, then goal amounts; kept amounts are never changed.
+// Returns the total shortfall that was cut.
+func fitPlan(plan []*plannedCategory, available int64) int64 {
+	totals := make(map[string]int64)
+	for _, category := range plan {
+		totals[category.Basis] += category.proposed
+	}
+	total := totals[planBasisGoal] + totals[planBasisAverage] + totals[planBasisNone]
+	if total <= available {
+		return 0
+	}
+
+	remaining := max(available-totals[planBasisNone], 0)
+	for _, basis := range []string{planBasisGoal, planBasisAverage} {
+		scale := 1.0
+		if totals[basis] > remaining {
+			scale = float64(remaining) / float64(totals[basis])
+		}
+		for _, category := range plan {
+			if category.Basis != basis || scale == 1 {
+				continue
+			}
+			reduced := int64(math.Floor(float64(category.proposed) * scale))
+			category.Rationale += fmt.Sprintf("; reduced from %s to fit Ready to Assign", ynab.FormatCurrency(category.proposed))
+			category.proposed = reduced
+		}
+		remaining = max(remaining-totals[basis], 0)
+	}
+	return total - available
+}
+
+// NewPlanNextMonthTool creates the plan_next_month tool
+func NewPlanNextMonthTool(client *ynab.Client) ToolDefinition {
+	tool := mcp.Tool{
+		Name:        "plan_next_month",
+		Description: "Propose assigned amounts for every category in the upcoming month. Categories with goals get what the goal still needs (YNAB's underfunded amount, with the target and cadence in the rationale); categories without goals get their average monthly spending less any money carried over. The plan is scaled to fit the expected Ready to Assign (current Ready to Assign plus average income), cutting non-goal amounts first. Returns the plan with a rationale per category; with confirm=true it is applied.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"budget_id": map[string]interface{}{
+					"type":        "string",
+					"description": "The ID of the budget",
+				},
+				"month": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional: month to plan in YYYY-MM format (default: next month)",
+				},
+				"history_months": map[string]interface{}{
+					"type":        "number",
+					"description": fmt.Sprintf("Optional: completed months of spending to average (1-12, default %d)", defaultPlanHistoryMonths),
+					"minimum":     1,
+					"maximum":     12,
+				},
+				"expected_income": map[string]interface{}{
+					"type":        "number",
+					"description": "Optional: income expected in the planned month (default: average income over history_months for a future month, 0 otherwise)",
+				},
+				"confirm": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Optional: assign the proposed amounts (default false, preview only)",
+				},
+			},
+			Required: []string{"budget_id"},
+		},
+	}
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		budgetID, ok := args["budget_id"].(string)
+		if !ok || budgetID == "" {
+			return mcp.NewToolResultError("budget_id is required"), nil
+		}
+
+		current, err := parseMonth(getCurrentMonth())
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid month format: %v", err)), nil
+		}
+		month := getMonthString(current.AddDate(0, 1, 0))
+		if monthArg, ok := args["month"].(string); ok && monthArg != "" {
+			month = monthArg
+		}
+		planned, err := parseMonth(month)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid month format: %v", err)), nil
+		}
+
+		historyMonths := defaultPlanHistoryMonths
+		if historyFloat, ok := args["history_months"].(float64); ok {
+			historyMonths = int(historyFloat)
+			if historyMonths < 1 || historyMonths > 12 {
+				return mcp.NewToolResultError("history_months must be between 1 and 12"), nil
+			}
+		}
+
+		confirm, _ := args["confirm"].(bool)
+
+		budgetMonth, err := client.GetMonth(budgetID, month+"-01")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch month %s: %v", month, err)), nil
+		}
+
+		// Spending history comes from the months before the planned one,
+		// stopping before the current month since it isn't complete
+		historyEnd := planned
+		if historyEnd.After(current) {
+			historyEnd = current
+		}
+		spending := make(map[string]int64)
+		totalIncome := int64(0)
+		for i := historyMonths; i >= 1; i-- {
+			m := getMonthString(historyEnd.AddDate(0, -i, 0))
+			history, err := client.GetMonth(budgetID, m+"-01")
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch month %s: %v", m, err)), nil
+			}
+			totalIncome += history.Income
+			for _, category := range history.Categories {
+				spending[category.ID] += -category.Activity
+			}
+		}
+
+		expectedIncome := int64(0)
+		if incomeFloat, ok := args["expected_income"].(float64); ok {
+			expectedIncome = ynab.FloatToMilliunits(incomeFloat)
+		} else if month > getCurrentMonth() {
+			expectedIncome = totalIncome / int64(historyMonths)
+		}
+
+		groups, err := client.ListCategories(budgetID)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch categories: %v", err)), nil
+		}
+		groupNames := categoryGroupNameIndex(groups)
+		budgetCategories := make(map[string]bool)
+		for _, group := range groups {
+			if !isBudgetCategoryGroup(group) {
+				continue
+			}
+			for _, category := range group.Categories {
+				budgetCategories[category.ID] = true
+			}
+		}
+
+		// Money already assigned to planned categories is part of what can be re-planned
+		available := budgetMonth.ToBeBudgeted + expectedIncome
+		plan := make([]*plannedCategory, 0)
+		for _, category := range budgetMonth.Categories {
+			if category.Deleted || category.Hidden || !budgetCategories[category.ID] {
+				continue
+			}
+			available += category.Budgeted
+			proposed, basis, rationale := planCategory(category, spending[category.ID]/int64(historyMonths), historyMonths)
+			plan = append(plan, &plannedCategory{
+				CategoryID:        category.ID,
+				CategoryName:      category.Name,
+				CategoryGroupName: groupNames[category.ID],
+				Basis:             basis,
+				Rationale:         rationale,
+				current:           category.Budgeted,
+				proposed:          proposed,
+			})
+		}
+
+		shortfall := fitPlan(plan, available)
+
+		totalProposed := int64(0)
+		changed := make([]*plannedCategory, 0)
+		for _, category := range plan {
+			category.CurrentAssigned = ynab.MilliunitsToFloat(category.current)
+			category.ProposedAssigned = ynab.MilliunitsToFloat(category.proposed)
+			category.Change = ynab.MilliunitsToFloat(category.proposed - category.current)
+			totalProposed += category.proposed
+			if category.proposed != category.current {
+				changed = append(changed, category)
+			}
+		}
+
+		// Goals first, then by size
+		basisOrder := map[string]int{planBasisGoal: 0, planBasisAverage: 1, planBasisNone: 2}
+		sort.SliceStable(plan, func(i, j int) bool {
+			if plan[i].Basis != plan[j].Basis {
+				return basisOrder[plan[i].Basis] < basisOrder[plan[j].Basis]
+			}
+			return plan[i].proposed > plan[j].proposed
+		})
+
+		// Build result
+		result := map[string]interface{}{
+			"month":                    month,
+			"history_months":           historyMonths,
+			"ready_to_assign":          ynab.MilliunitsToFloat(budgetMonth.ToBeBudgeted),
+			"expected_income":          ynab.MilliunitsToFloat(expectedIncome),
+			"expected_ready_to_assign": ynab.MilliunitsToFloat(available),
+			"total_proposed":           ynab.MilliunitsToFloat(totalProposed),
+			"left_to_assign":           ynab.MilliunitsToFloat(available - totalProposed),
+			"categories":               plan,
+			"categories_changed":       len(changed),
+		}
+		if shortfall > 0 {
+			result["shortfall"] = ynab.MilliunitsToFloat(shortfall)
+		}
+
+		if confirm {
+			progress := newProgressReporter(ctx, request)
+			for i, category := range changed {
+				if _, err := client.UpdateMonthCategory(budgetID, month+"-01", category.CategoryID, category.proposed); err != nil {
+					return mcp.NewToolResultError(fmt.Sprintf("Failed to update %s after %d of %d categories were updated: %v",
+						category.CategoryName, i, len(changed), err)), nil
+				}
+				progress(float64(i+1), float64(len(changed)), fmt.Sprintf("Updated %d of %d categories", i+1, len(changed)))
+			}
+			result["applied"] = true
+		} else {
+			result["applied"] = false
+			result["note"] = "No changes were made. Call again with confirm=true to assign the proposed amounts."
+		}
+
+		jsonResult, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to format result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonResult)), nil
+	}
+
+	return ToolDefinition{Tool: tool, Handler: handler}
+}
@@ -0,0 +1,66 @@
+package tools
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// progressNotification is the MCP method for progress updates
+const progressNotification = "notifications/progress"
+
+// newProgressReporter returns a progressFunc for a tool call. When the client
+// sent a progress token with the request, every report is also delivered to
+// the client as a progress notification; otherwise progress is only logged.
+// MCP requires progress to increase with each notification, so reports that
+// don't move forward are logged but not sent.
+func newProgressReporter(ctx context.Context, request mcp.CallToolRequest) progressFunc {
+	if request.Params.Meta == nil || request.Params.Meta.ProgressToken == nil {
+		return logProgress
+	}
+	srv := server.ServerFromContext(ctx)
+	if srv == nil {
+		return logProgress
+	}
+
+	token := request.Params.Meta.ProgressToken
+	sent := false
+	last := 0.0
+	return func(progress, total float64, message string) {
+		logProgress(progress, total, message)
+		if sent && progress <= last {
+			return
+		}
+		sent, last = true, progress
+
+		params := map[string]any{
+			"progressToken": token,
+			"progress":      progress,
+		}
+		if total > 0 {
+			params["total"] = total
+		}
+		if message != "" {
+			params["message"] = message
+		}
+		if err := srv.SendNotificationToClient(ctx, progressNotification, params); err != nil {
+			slog.Debug("Failed to send progress notification", "error", err)
+		}
+	}
+}
+
+// progressPhase maps one phase of a multi-step call onto a shared percentage
+// scale, so a reporter that only sends increasing progress sees every phase.
+// The phase covers start to start+span percent; phases with an unknown total
+// report their start until they finish.
+func progressPhase(progress progressFunc, start, span float64) progressFunc {
+	return func(done, total float64, message string) {
+		fraction := 0.0
+		if total > 0 {
+			fraction = min(done/total, 1)
+		}
+		progress(start+span*fraction, 100, message)
+	}
+}
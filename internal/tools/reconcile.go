@@ -0,0 +1,314 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jeff-french/ynab-mcp-server/internal/ynab"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// maxSubsetSumStates bounds the memory used when searching for uncleared
+	// transactions that explain a reconciliation discrepancy
+	maxSubsetSumStates = 200000
+
+	reconciliationAdjustmentPayee = "Reconciliation Balance Adjustment"
+)
+
+// subsetSumResult is the best combination of candidates found for a target
+type subsetSumResult struct {
+	Indexes []int
+	Sum     int64
+	Exact   bool
+}
+
+// findSubsetSum searches for a subset of amounts summing to target, preferring
+// subsets with fewer transactions. If no exact subset exists, the subset with
+// the closest sum is returned. The search is a breadth-first dynamic program
+// over reachable sums, capped at maxSubsetSumStates.
+func findSubsetSum(amounts []int64, target int64) subsetSumResult {
+	type state struct {
+		sum     int64
+		indexes []int
+	}
+	// Subsets only grow with higher indexes, so two subsets with the same sum
+	// can only be merged when they also end at the same index
+	type stateKey struct {
+		sum  int64
+		last int
+	}
+
+	best := subsetSumResult{Indexes: []int{}}
+	if target == 0 {
+		best.Exact = true
+		return best
+	}
+
+	seen := make(map[stateKey]bool)
+	frontier := []state{{sum: 0}}
+	bestGap := absMilliunits(target)
+
+	// Each round adds one more transaction, so the first exact hit is minimal
+	for round := 0; round < len(amounts) && len(frontier) > 0; round++ {
+		next := make([]state, 0)
+		for _, current := range frontier {
+			start := 0
+			if len(current.indexes) > 0 {
+				start = current.indexes[len(current.indexes)-1] + 1
+			}
+			for i := start; i < len(amounts); i++ {
+				sum := current.sum + amounts[i]
+				key := stateKey{sum: sum, last: i}
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+
+				indexes := append(append([]int(nil), current.indexes...), i)
+				if gap := absMilliunits(target - sum); gap < bestGap {
+					bestGap = gap
+					best = subsetSumResult{Indexes: indexes, Sum: sum}
+				}
+				if sum == target {
+					best.Exact = true
+					return best
+				}
+				if len(seen) < maxSubsetSumStates {
+					next = append(next, state{sum: sum, indexes: indexes})
+				}
+			}
+		}
+		frontier = next
+	}
+
+	return best
+}
+
+// NewReconcileAccountTool creates the reconcile_account tool
+func NewReconcileAccountTool(client *ynab.Client) ToolDefinition {
+	tool := mcp.Tool{
+		Name:        "reconcile_account",
+		Description: "Reconcile an account against a bank statement. Computes the cleared balance as of the statement date, reports the discrepancy, and proposes which uncleared transactions would close the gap. With confirm=true it clears those transactions, marks everything cleared up to the statement date as reconciled, and optionally creates a balance adjustment for any remaining difference.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"budget_id": map[string]interface{}{
+					"type":        "string",
+					"description": "The ID of the budget",
+				},
+				"account_id": map[string]interface{}{
+					"type":        "string",
+					"description": "The ID of the account to reconcile",
+				},
+				"statement_date": map[string]interface{}{
+					"type":        "string",
+					"description": "Statement closing date in YYYY-MM-DD format",
+				},
+				"statement_balance": map[string]interface{}{
+					"type":        "number",
+					"description": "Statement ending balance in currency units (negative for credit card debt)",
+				},
+				"transaction_ids": map[string]interface{}{
+					"type":        "array",
+					"description": "Optional: uncleared transaction IDs to clear instead of the proposed ones",
+					"items": map[string]interface{}{
+						"type": "string",
+					},
+				},
+				"confirm": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Optional: apply the reconciliation (default false, report only)",
+				},
+				"create_adjustment": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Optional: with confirm=true, create a reconciled adjustment transaction for any remaining difference (default false)",
+				},
+			},
+			Required: []string{"budget_id", "account_id", "statement_date", "statement_balance"},
+		},
+	}
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		budgetID, ok := args["budget_id"].(string)
+		if !ok || budgetID == "" {
+			return mcp.NewToolResultError("budget_id is required"), nil
+		}
+
+		accountID, ok := args["account_id"].(string)
+		if !ok || accountID == "" {
+			return mcp.NewToolResultError("account_id is required"), nil
+		}
+
+		statementDate, ok := args["statement_date"].(string)
+		if !ok || statementDate == "" {
+			return mcp.NewToolResultError("statement_date is required (YYYY-MM-DD format)"), nil
+		}
+		if _, err := parseDate(statementDate); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("statement_date: %v", err)), nil
+		}
+
+		statementBalanceFloat, ok := args["statement_balance"].(float64)
+		if !ok {
+			return mcp.NewToolResultError("statement_balance is required and must be a number"), nil
+		}
+		statementBalance := ynab.FloatToMilliunits(statementBalanceFloat)
+
+		confirm, _ := args["confirm"].(bool)
+		createAdjustment, _ := args["create_adjustment"].(bool)
+		chosenIDs := stringSliceArg(args, "transaction_ids")
+
+		account, err := client.GetAccount(budgetID, accountID)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch account: %v", err)), nil
+		}
+
+		transactions, err := client.ListAccountTransactions(budgetID, accountID, nil)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch transactions: %v", err)), nil
+		}
+
+		// Split transactions up to the statement date into cleared and uncleared
+		clearedBalance := int64(0)
+		cleared := make([]ynab.Transaction, 0)
+		uncleared := make([]ynab.Transaction, 0)
+		for _, tx := range transactions {
+			if tx.Deleted || tx.Date > statementDate {
+				continue
+			}
+			switch tx.Cleared {
+			case "cleared":
+				clearedBalance += tx.Amount
+				cleared = append(cleared, tx)
+			case "reconciled":
+				clearedBalance += tx.Amount
+			default:
+				uncleared = append(uncleared, tx)
+			}
+		}
+
+		// Newest first: recent uncleared transactions are the likeliest to be on the statement
+		sortTransactions(uncleared, sortDateDesc)
+
+		discrepancy := statementBalance - clearedBalance
+
+		var toClear []ynab.Transaction
+		exact := discrepancy == 0
+		if len(chosenIDs) > 0 {
+			byID := make(map[string]ynab.Transaction, len(uncleared))
+			for _, tx := range uncleared {
+				byID[tx.ID] = tx
+			}
+			for _, id := range chosenIDs {
+				tx, ok := byID[id]
+				if !ok {
+					return mcp.NewToolResultError(fmt.Sprintf("transaction %s is not an uncleared transaction on or before %s", id, statementDate)), nil
+				}
+				toClear = append(toClear, tx)
+			}
+		} else if discrepancy != 0 && len(uncleared) > 0 {
+			amounts := make([]int64, len(uncleared))
+			for i, tx := range uncleared {
+				amounts[i] = tx.Amount
+			}
+			match := findSubsetSum(amounts, discrepancy)
+			exact = match.Exact
+			sort.Ints(match.Indexes)
+			for _, index := range match.Indexes {
+				toClear = append(toClear, uncleared[index])
+			}
+		}
+
+		clearedSum := int64(0)
+		for _, tx := range toClear {
+			clearedSum += tx.Amount
+		}
+		remaining := discrepancy - clearedSum
+		if len(chosenIDs) > 0 {
+			exact = remaining == 0
+		}
+
+		var result strings.Builder
+		result.WriteString(fmt.Sprintf("Reconciliation: %s\n\n", account.Name))
+		result.WriteString(fmt.Sprintf("Statement Date: %s\n", statementDate))
+		result.WriteString(fmt.Sprintf("Statement Balance: %s\n", ynab.FormatCurrency(statementBalance)))
+		result.WriteString(fmt.Sprintf("Cleared Balance as of %s: %s\n", statementDate, ynab.FormatCurrency(clearedBalance)))
+		result.WriteString(fmt.Sprintf("Discrepancy: %s\n", ynab.FormatCurrency(discrepancy)))
+		result.WriteString(fmt.Sprintf("Uncleared transactions on or before statement date: %d\n\n", len(uncleared)))
+
+		switch {
+		case discrepancy == 0:
+			result.WriteString("✓ Cleared balance already matches the statement.\n\n")
+		case len(toClear) == 0:
+			result.WriteString("No combination of uncleared transactions reduces the discrepancy.\n\n")
+		default:
+			if exact {
+				result.WriteString(fmt.Sprintf("✓ Clearing these %d transaction(s) closes the gap exactly:\n\n", len(toClear)))
+			} else {
+				result.WriteString(fmt.Sprintf("⚠️  No exact match. Clearing these %d transaction(s) gets closest (remaining difference %s):\n\n",
+					len(toClear), ynab.FormatCurrency(remaining)))
+			}
+			for i, tx := range toClear {
+				writeTransactionEntry(&result, i+1, tx)
+			}
+		}
+
+		if !confirm {
+			result.WriteString("No changes were made. Call again with confirm=true to reconcile")
+			if remaining != 0 {
+				result.WriteString(" (add create_adjustment=true to record the remaining difference)")
+			}
+			result.WriteString(".\n")
+			return mcp.NewToolResultText(result.String()), nil
+		}
+
+		if remaining != 0 && !createAdjustment {
+			result.WriteString(fmt.Sprintf("Not reconciled: a difference of %s remains. Pick different transaction_ids or pass create_adjustment=true.\n",
+				ynab.FormatCurrency(remaining)))
+			return mcp.NewToolResultText(result.String()), nil
+		}
+
+		// Lock everything that is now cleared up to the statement date
+		plans := make([]plannedUpdate, 0, len(cleared)+len(toClear))
+		for _, tx := range append(cleared, toClear...) {
+			if plan, changed := planBulkChange(tx, bulkChange{Cleared: "reconciled"}, nil); changed {
+				plans = append(plans, plan)
+			}
+		}
+		if len(plans) > 0 {
+			if _, err := applyPlannedUpdates(client, budgetID, plans, newProgressReporter(ctx, request)); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Failed to reconcile transactions: %v", err)), nil
+			}
+		}
+		result.WriteString(fmt.Sprintf("Marked %d transaction(s) as reconciled.\n", len(plans)))
+
+		if remaining != 0 {
+			req := &ynab.CreateTransactionRequest{}
+			req.Transaction.AccountID = accountID
+			req.Transaction.Date = statementDate
+			req.Transaction.Amount = remaining
+			req.Transaction.PayeeName = reconciliationAdjustmentPayee
+			req.Transaction.Cleared = "reconciled"
+			req.Transaction.Approved = true
+
+			adjustment, err := client.CreateTransaction(budgetID, req)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Failed to create adjustment transaction: %v", err)), nil
+			}
+			result.WriteString(fmt.Sprintf("Created balance adjustment of %s (ID: %s). Categorize it if needed.\n",
+				ynab.FormatCurrency(adjustment.Amount), adjustment.ID))
+		}
+
+		result.WriteString("\n✓ Account reconciled.\n")
+		return mcp.NewToolResultText(result.String()), nil
+	}
+
+	return ToolDefinition{Tool: tool, Handler: handler}
+}
@@ -0,0 +1,35 @@
+package tools
+
+import "testing"
+
+func TestFindSubsetSumRepeatedAmounts(t *testing.T) {
+	tests := []struct {
+		name    string
+		amounts []int64
+		target  int64
+		want    int
+	}{
+		{"all three", []int64{-10000, -10000, -20000}, -40000, 3},
+		{"repeated fives", []int64{-5000, -3000, -2000, -5000}, -15000, 4},
+		{"pair of repeats", []int64{-7000, -7000, -1000}, -14000, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := findSubsetSum(tt.amounts, tt.target)
+			if !got.Exact || got.Sum != tt.target {
+				t.Fatalf("findSubsetSum(%v, %d) = %+v, want an exact match", tt.amounts, tt.target, got)
+			}
+			if len(got.Indexes) != tt.want {
+				t.Errorf("findSubsetSum(%v, %d) used %d transactions, want %d", tt.amounts, tt.target, len(got.Indexes), tt.want)
+			}
+			sum := int64(0)
+			for _, i := range got.Indexes {
+				sum += tt.amounts[i]
+			}
+			if sum != tt.target {
+				t.Errorf("indexes %v sum to %d, want %d", got.Indexes, sum, tt.target)
+			}
+		})
+	}
+}
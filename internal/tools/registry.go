@@ -1,6 +1,7 @@
 package tools
 
 import (
+	"github.com/jeff-french/ynab-mcp-server/internal/config"
 	"github.com/jeff-french/ynab-mcp-server/internal/ynab"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -13,25 +14,39 @@ type ToolDefinition struct {
 }
 
 // GetAllTools returns all available YNAB MCP tools
-func GetAllTools(client *ynab.Client) []ToolDefinition {
+func GetAllTools(client *ynab.Client, cfg *config.Config) []ToolDefinition {
 	return []ToolDefinition{
 		// Budget tools
 		NewListBudgetsTool(client),
 		NewGetBudgetTool(client),
+		NewGetConsolidatedOverviewTool(client, cfg.BaseCurrency, cfg.ExchangeRates),
 
 		// Account tools
 		NewListAccountsTool(client),
 		NewGetAccountTool(client),
+		NewReconcileAccountTool(client),
 
 		// Transaction tools
 		NewListTransactionsTool(client),
 		NewGetTransactionTool(client),
 		NewCreateTransactionTool(client),
 		NewUpdateTransactionTool(client),
+		NewSearchTransactionsTool(client),
+		NewBulkUpdateTransactionsTool(client),
+		NewApproveTransactionsTool(client),
+		NewFindDuplicateTransactionsTool(client),
+
+		// Categorization tools
+		NewListRulesTool(cfg.CategorizationRules),
+		NewTestRuleTool(client, cfg.CategorizationRules),
+		NewApplyCategorizationRulesTool(client, cfg.CategorizationRules),
+		NewSuggestCategoriesTool(client),
 
 		// Category tools
 		NewListCategoriesTool(client),
 		NewGetCategoryTool(client),
+		NewFindOverspendingTool(client),
+		NewPlanNextMonthTool(client),
 
 		// Payee tools
 		NewListPayeesTool(client),
@@ -42,5 +57,19 @@ func GetAllTools(client *ynab.Client) []ToolDefinition {
 		NewGetBudgetSummaryTool(client),
 		NewGetPayeeSummaryTool(client),
 		NewGetAccountBalancesTool(client),
+		NewAggregateTransactionsTool(client),
+
+		// Analysis tools
+		NewDetectSubscriptionsTool(client),
+		NewForecastCashFlowTool(client),
+		NewGetNetWorthHistoryTool(client),
+		NewGetBudgetVarianceTool(client),
+		NewDetectSpendingAnomaliesTool(client),
+		NewGetGoalProgressTool(client),
+		NewPlanDebtPayoffTool(client),
+		NewComparePeriodsTool(client),
+		NewListTagsTool(client),
+		NewGetSpendingByTagTool(client),
+		NewGetIncomeReportTool(client),
 	}
 }
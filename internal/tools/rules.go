@@ -0,0 +1,221 @@
+package tools
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jeff-french/ynab-mcp-server/internal/config"
+	"github.com/jeff-french/ynab-mcp-server/internal/ynab"
+)
+
+// Helper functions for rule-based categorization
+
+// compiledRule is a categorization rule with its patterns compiled and
+// amounts converted to milliunits
+type compiledRule struct {
+	config.CategorizationRule
+	Index      int // 1-based position in the config file
+	payeeRegex *regexp.Regexp
+	memoRegex  *regexp.Regexp
+	minAmount  *int64
+	maxAmount  *int64
+}
+
+// ruleConditionResult records whether a single rule condition matched
+type ruleConditionResult struct {
+	Condition string
+	Matched   bool
+}
+
+// compileRules compiles configured rules in order
+func compileRules(rules []config.CategorizationRule) ([]*compiledRule, error) {
+	compiled := make([]*compiledRule, 0, len(rules))
+	for i, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return nil, fmt.Errorf("rule %d (%s): %w", i+1, rule.Name, err)
+		}
+
+		cr := &compiledRule{CategorizationRule: rule, Index: i + 1}
+		if rule.PayeeRegex != "" {
+			cr.payeeRegex = regexp.MustCompile(rule.PayeeRegex)
+		}
+		if rule.MemoRegex != "" {
+			cr.memoRegex = regexp.MustCompile(rule.MemoRegex)
+		}
+		if rule.MinAmount != nil {
+			minAmount := ynab.FloatToMilliunits(*rule.MinAmount)
+			cr.minAmount = &minAmount
+		}
+		if rule.MaxAmount != nil {
+			maxAmount := ynab.FloatToMilliunits(*rule.MaxAmount)
+			cr.maxAmount = &maxAmount
+		}
+		compiled = append(compiled, cr)
+	}
+	return compiled, nil
+}
+
+// displayName returns the rule name, or its position if it has none
+func (r *compiledRule) displayName() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return fmt.Sprintf("Rule %d", r.Index)
+}
+
+// evaluate checks each condition of the rule against a transaction. The payee
+// pattern is tried against the payee name and both import payee names.
+func (r *compiledRule) evaluate(tx ynab.Transaction) []ruleConditionResult {
+	results := make([]ruleConditionResult, 0, 5)
+
+	if r.payeeRegex != nil {
+		matched := false
+		for _, name := range []string{tx.PayeeName, tx.ImportPayeeName, tx.ImportPayeeNameOriginal} {
+			if name != "" && r.payeeRegex.MatchString(name) {
+				matched = true
+				break
+			}
+		}
+		results = append(results, ruleConditionResult{Condition: fmt.Sprintf("payee matches /%s/", r.PayeeRegex), Matched: matched})
+	}
+	if r.memoRegex != nil {
+		results = append(results, ruleConditionResult{
+			Condition: fmt.Sprintf("memo matches /%s/", r.MemoRegex),
+			Matched:   r.memoRegex.MatchString(tx.Memo),
+		})
+	}
+	if r.minAmount != nil {
+		results = append(results, ruleConditionResult{
+			Condition: fmt.Sprintf("amount >= %s", ynab.FormatCurrency(*r.minAmount)),
+			Matched:   tx.Amount >= *r.minAmount,
+		})
+	}
+	if r.maxAmount != nil {
+		results = append(results, ruleConditionResult{
+			Condition: fmt.Sprintf("amount <= %s", ynab.FormatCurrency(*r.maxAmount)),
+			Matched:   tx.Amount <= *r.maxAmount,
+		})
+	}
+	if r.AccountID != "" {
+		results = append(results, ruleConditionResult{
+			Condition: fmt.Sprintf("account is %s", r.AccountID),
+			Matched:   tx.AccountID == r.AccountID,
+		})
+	}
+
+	return results
+}
+
+// describeConditions returns a human readable list of the rule's conditions
+func (r *compiledRule) describeConditions() []string {
+	// Condition labels don't depend on the transaction being evaluated
+	results := r.evaluate(ynab.Transaction{})
+	conditions := make([]string, 0, len(results))
+	for _, result := range results {
+		conditions = append(conditions, result.Condition)
+	}
+	return conditions
+}
+
+// matches reports whether every condition of the rule matches the transaction
+func (r *compiledRule) matches(tx ynab.Transaction) bool {
+	for _, result := range r.evaluate(tx) {
+		if !result.Matched {
+			return false
+		}
+	}
+	return true
+}
+
+// findMatchingRule returns the first rule matching the transaction, or nil
+func findMatchingRule(rules []*compiledRule, tx ynab.Transaction) *compiledRule {
+	for _, rule := range rules {
+		if rule.matches(tx) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// describeActions returns a human readable list of the rule's actions
+func (r *compiledRule) describeActions(categoryNames map[string]string) []string {
+	actions := make([]string, 0, 4)
+	if r.CategoryID != "" {
+		actions = append(actions, fmt.Sprintf("set category to %s", lookupName(categoryNames, r.CategoryID)))
+	}
+	if r.RenamePayee != "" {
+		actions = append(actions, fmt.Sprintf("rename payee to %q", r.RenamePayee))
+	}
+	if r.FlagColor != "" {
+		actions = append(actions, fmt.Sprintf("flag %s", r.FlagColor))
+	}
+	if r.MemoTemplate != "" {
+		actions = append(actions, fmt.Sprintf("set memo from template %q", r.MemoTemplate))
+	}
+	return actions
+}
+
+// planUpdate builds the bulk update the rule would make to a transaction,
+// skipping fields that already have the target value
+func (r *compiledRule) planUpdate(tx ynab.Transaction, categoryNames map[string]string) (plannedUpdate, bool) {
+	plan := plannedUpdate{
+		Transaction: tx,
+		Update:      ynab.BulkTransactionUpdate{ID: tx.ID},
+	}
+
+	if r.CategoryID != "" && tx.CategoryID != r.CategoryID {
+		plan.Update.CategoryID = r.CategoryID
+		plan.Changes = append(plan.Changes, fmt.Sprintf("category: %s → %s",
+			displayOrNone(tx.CategoryName), lookupName(categoryNames, r.CategoryID)))
+	}
+	if r.RenamePayee != "" && tx.PayeeName != r.RenamePayee {
+		plan.Update.PayeeName = r.RenamePayee
+		plan.Changes = append(plan.Changes, fmt.Sprintf("payee: %s → %s", displayOrNone(tx.PayeeName), r.RenamePayee))
+	}
+	if r.FlagColor != "" && tx.FlagColor != r.FlagColor {
+		plan.Update.FlagColor = r.FlagColor
+		plan.Changes = append(plan.Changes, fmt.Sprintf("flag: %s → %s", displayOrNone(tx.FlagColor), r.FlagColor))
+	}
+	if r.MemoTemplate != "" {
+		// An empty expansion can't be sent: the update would leave the memo unchanged
+		memo := expandMemoTemplate(r.MemoTemplate, tx)
+		if memo != "" && memo != tx.Memo && !memoTemplateApplied(r.MemoTemplate, tx) {
+			plan.Update.Memo = memo
+			plan.Changes = append(plan.Changes, fmt.Sprintf("memo: %s → %s", displayOrNone(tx.Memo), memo))
+		}
+	}
+
+	return plan, len(plan.Changes) > 0
+}
+
+// expandMemoTemplate substitutes transaction fields into a memo template.
+// Supported placeholders: {memo}, {payee}, {import_payee}, {date}, {amount}, {account}.
+func expandMemoTemplate(template string, tx ynab.Transaction) string {
+	replacer := strings.NewReplacer(
+		"{memo}", tx.Memo,
+		"{payee}", tx.PayeeName,
+		"{import_payee}", tx.ImportPayeeNameOriginal,
+		"{date}", tx.Date,
+		"{amount}", ynab.FormatCurrency(tx.Amount),
+		"{account}", tx.AccountName,
+	)
+	return strings.TrimSpace(replacer.Replace(template))
+}
+
+// memoTemplateApplied reports whether the memo already has the text a
+// template wraps around {memo}, so running the rule again doesn't keep
+// prepending or appending it
+func memoTemplateApplied(template string, tx ynab.Transaction) bool {
+	first := strings.Index(template, "{memo}")
+	if first < 0 {
+		return false
+	}
+	last := strings.LastIndex(template, "{memo}")
+	prefix := strings.TrimSpace(expandMemoTemplate(template[:first], tx))
+	suffix := strings.TrimSpace(expandMemoTemplate(template[last+len("{memo}"):], tx))
+	if prefix == "" && suffix == "" {
+		return true
+	}
+	return strings.HasPrefix(tx.Memo, prefix) && strings.HasSuffix(tx.Memo, suffix)
+}
@@ -0,0 +1,325 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jeff-french/ynab-mcp-server/internal/config"
+	"github.com/jeff-french/ynab-mcp-server/internal/ynab"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const noRulesMessage = "No categorization rules configured. Add a categorization_rules list to the config file to use rule-based categorization."
+
+// NewListRulesTool creates the list_rules tool
+func NewListRulesTool(rules []config.CategorizationRule) ToolDefinition {
+	compiled, compileErr := compileRules(rules)
+
+	tool := mcp.Tool{
+		Name:        "list_rules",
+		Description: "List the categorization rules defined in the server config file, in evaluation order. The first matching rule wins.",
+		InputSchema: mcp.ToolInputSchema{
+			Type:       "object",
+			Properties: map[string]interface{}{},
+			Required:   []string{},
+		},
+	}
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if compileErr != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid categorization rules: %v", compileErr)), nil
+		}
+		if len(compiled) == 0 {
+			return mcp.NewToolResultText(noRulesMessage), nil
+		}
+
+		var result strings.Builder
+		result.WriteString(fmt.Sprintf("Found %d categorization rule(s):\n\n", len(compiled)))
+
+		for _, rule := range compiled {
+			result.WriteString(fmt.Sprintf("%d. %s\n", rule.Index, rule.displayName()))
+			result.WriteString("   When:\n")
+			for _, condition := range rule.describeConditions() {
+				result.WriteString(fmt.Sprintf("     - %s\n", condition))
+			}
+			result.WriteString("   Then:\n")
+			for _, action := range rule.describeActions(nil) {
+				result.WriteString(fmt.Sprintf("     - %s\n", action))
+			}
+			result.WriteString("\n")
+		}
+
+		return mcp.NewToolResultText(result.String()), nil
+	}
+
+	return ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// NewTestRuleTool creates the test_rule tool
+func NewTestRuleTool(client *ynab.Client, rules []config.CategorizationRule) ToolDefinition {
+	compiled, compileErr := compileRules(rules)
+
+	tool := mcp.Tool{
+		Name:        "test_rule",
+		Description: "Check which categorization rule would fire for a transaction. Pass a transaction_id, or describe a hypothetical transaction with payee_name, memo, amount and account_id. Shows each rule's conditions and whether they matched.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"budget_id": map[string]interface{}{
+					"type":        "string",
+					"description": "The ID of the budget (required with transaction_id)",
+				},
+				"transaction_id": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional: ID of an existing transaction to test",
+				},
+				"payee_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional: payee name of a hypothetical transaction",
+				},
+				"memo": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional: memo of a hypothetical transaction",
+				},
+				"amount": map[string]interface{}{
+					"type":        "number",
+					"description": "Optional: signed amount in currency units of a hypothetical transaction (outflows are negative)",
+				},
+				"account_id": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional: account ID of a hypothetical transaction",
+				},
+			},
+			Required: []string{},
+		},
+	}
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		if compileErr != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid categorization rules: %v", compileErr)), nil
+		}
+		if len(compiled) == 0 {
+			return mcp.NewToolResultText(noRulesMessage), nil
+		}
+
+		var tx ynab.Transaction
+		if transactionID, ok := args["transaction_id"].(string); ok && transactionID != "" {
+			budgetID, ok := args["budget_id"].(string)
+			if !ok || budgetID == "" {
+				return mcp.NewToolResultError("budget_id is required with transaction_id"), nil
+			}
+			fetched, err := client.GetTransaction(budgetID, transactionID)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch transaction: %v", err)), nil
+			}
+			tx = *fetched
+		} else {
+			tx.PayeeName, _ = args["payee_name"].(string)
+			tx.Memo, _ = args["memo"].(string)
+			tx.AccountID, _ = args["account_id"].(string)
+			if amount, ok := args["amount"].(float64); ok {
+				tx.Amount = ynab.FloatToMilliunits(amount)
+			}
+			if tx.PayeeName == "" && tx.Memo == "" && tx.AccountID == "" && args["amount"] == nil {
+				return mcp.NewToolResultError("transaction_id or at least one of payee_name, memo, amount, account_id is required"), nil
+			}
+		}
+
+		var result strings.Builder
+		result.WriteString("Testing transaction:\n")
+		result.WriteString(fmt.Sprintf("  Payee: %s\n", displayOrNone(tx.PayeeName)))
+		if tx.ImportPayeeNameOriginal != "" {
+			result.WriteString(fmt.Sprintf("  Imported Payee: %s\n", tx.ImportPayeeNameOriginal))
+		}
+		result.WriteString(fmt.Sprintf("  Memo: %s\n", displayOrNone(tx.Memo)))
+		result.WriteString(fmt.Sprintf("  Amount: %s\n", ynab.FormatCurrency(tx.Amount)))
+		if tx.AccountID != "" {
+			result.WriteString(fmt.Sprintf("  Account: %s\n", tx.AccountID))
+		}
+		result.WriteString("\n")
+
+		var fired *compiledRule
+		for _, rule := range compiled {
+			conditions := rule.evaluate(tx)
+			matched := true
+			for _, condition := range conditions {
+				if !condition.Matched {
+					matched = false
+				}
+			}
+
+			status := "no match"
+			if matched && fired == nil {
+				status = "MATCH - this rule fires"
+				fired = rule
+			} else if matched {
+				status = "match (shadowed by an earlier rule)"
+			}
+
+			result.WriteString(fmt.Sprintf("%d. %s: %s\n", rule.Index, rule.displayName(), status))
+			for _, condition := range conditions {
+				symbol := "✗"
+				if condition.Matched {
+					symbol = "✓"
+				}
+				result.WriteString(fmt.Sprintf("   %s %s\n", symbol, condition.Condition))
+			}
+		}
+
+		result.WriteString("\n")
+		if fired == nil {
+			result.WriteString("No rule matches this transaction.\n")
+		} else {
+			result.WriteString(fmt.Sprintf("Rule %q would %s.\n", fired.displayName(), strings.Join(fired.describeActions(nil), ", ")))
+		}
+
+		return mcp.NewToolResultText(result.String()), nil
+	}
+
+	return ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// NewApplyCategorizationRulesTool creates the apply_categorization_rules tool
+func NewApplyCategorizationRulesTool(client *ynab.Client, rules []config.CategorizationRule) ToolDefinition {
+	compiled, compileErr := compileRules(rules)
+
+	tool := mcp.Tool{
+		Name:        "apply_categorization_rules",
+		Description: "Evaluate the configured categorization rules against uncategorized or unapproved transactions, show a preview table of the matches, and apply them in bulk. Changes are previewed by default; call again with dry_run=false to apply them.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"budget_id": map[string]interface{}{
+					"type":        "string",
+					"description": "The ID of the budget",
+				},
+				"type": map[string]interface{}{
+					"type":        "string",
+					"description": "Which transactions to evaluate: 'uncategorized' (default) or 'unapproved'",
+					"enum":        []string{"uncategorized", "unapproved"},
+				},
+				"since_date": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional: only transactions on or after this date (YYYY-MM-DD)",
+				},
+				"account_id": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional: only transactions in this account",
+				},
+				"approve": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Optional: also approve transactions that a rule matched (default false)",
+				},
+				"dry_run": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Optional: preview the changes without applying them (default true; pass false to apply)",
+				},
+			},
+			Required: []string{"budget_id"},
+		},
+	}
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		budgetID, ok := args["budget_id"].(string)
+		if !ok || budgetID == "" {
+			return mcp.NewToolResultError("budget_id is required"), nil
+		}
+
+		if compileErr != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid categorization rules: %v", compileErr)), nil
+		}
+		if len(compiled) == 0 {
+			return mcp.NewToolResultText(noRulesMessage), nil
+		}
+
+		filter, err := parseTransactionFilter(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if filter.Type == "" {
+			filter.Type = "uncategorized"
+		}
+
+		approve, _ := args["approve"].(bool)
+		dryRun := dryRunArg(args)
+
+		transactions, err := fetchFilteredTransactions(client, budgetID, filter)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch transactions: %v", err)), nil
+		}
+
+		categoryGroups, err := client.ListCategories(budgetID)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch categories: %v", err)), nil
+		}
+		categoryNames := categoryNameIndex(categoryGroups)
+
+		plans := make([]plannedUpdate, 0)
+		ruleNames := make([]string, 0)
+		matched := 0
+		for _, tx := range transactions {
+			rule := findMatchingRule(compiled, tx)
+			if rule == nil {
+				continue
+			}
+			matched++
+
+			plan, changed := rule.planUpdate(tx, categoryNames)
+			if approve && !tx.Approved {
+				approved := true
+				plan.Update.Approved = &approved
+				plan.Changes = append(plan.Changes, "approved: false → true")
+				changed = true
+			}
+			if !changed {
+				continue
+			}
+			plans = append(plans, plan)
+			ruleNames = append(ruleNames, rule.displayName())
+		}
+
+		var result strings.Builder
+		result.WriteString(fmt.Sprintf("Evaluated %d %s transaction(s): %d matched a rule (%d already up to date), %d did not.\n\n",
+			len(transactions), filter.Type, matched, matched-len(plans), len(transactions)-matched))
+
+		if len(plans) == 0 {
+			result.WriteString("Nothing to update.\n")
+			return mcp.NewToolResultText(result.String()), nil
+		}
+
+		result.WriteString("| # | Date | Payee | Amount | Rule | Changes |\n")
+		result.WriteString("|---|------|-------|--------|------|---------|\n")
+		for i, plan := range plans {
+			tx := plan.Transaction
+			result.WriteString(fmt.Sprintf("| %d | %s | %s | %s | %s | %s |\n",
+				i+1, tx.Date, tx.PayeeName, ynab.FormatCurrency(tx.Amount), ruleNames[i], strings.Join(plan.Changes, "; ")))
+		}
+		result.WriteString("\n")
+
+		if dryRun {
+			result.WriteString("Dry run: no changes were made. Call again with dry_run=false to apply.\n")
+			return mcp.NewToolResultText(result.String()), nil
+		}
+
+		updated, err := applyPlannedUpdates(client, budgetID, plans, newProgressReporter(ctx, request))
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to update transactions: %v", err)), nil
+		}
+
+		result.WriteString(fmt.Sprintf("Updated %d transaction(s) successfully!\n", len(updated)))
+		return mcp.NewToolResultText(result.String()), nil
+	}
+
+	return ToolDefinition{Tool: tool, Handler: handler}
+}
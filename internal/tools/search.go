@@ -0,0 +1,497 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jeff-french/ynab-mcp-server/internal/ynab"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// transactionFilter holds the criteria shared by tools that select transactions
+type transactionFilter struct {
+	AccountID       string
+	Type            string // uncategorized, unapproved (passed through to YNAB)
+	SinceDate       string
+	UntilDate       string
+	PayeeID         string
+	PayeeContains   string // lowercased
+	CategoryID      string
+	CategoryGroupID string
+	MemoContains    string // lowercased
+	MemoRegex       *regexp.Regexp
+	MinAmount       *int64 // milliunits, inclusive
+	MaxAmount       *int64 // milliunits, inclusive
+	Cleared         string
+	Approved        *bool
+	FlagColor       string
+
+	// categoryIDs is resolved from CategoryGroupID before matching
+	categoryIDs map[string]bool
+}
+
+// transactionFilterProperties returns the input schema properties for transactionFilter
+func transactionFilterProperties() map[string]interface{} {
+	return map[string]interface{}{
+		"account_id": map[string]interface{}{
+			"type":        "string",
+			"description": "Optional: only transactions in this account",
+		},
+		"type": map[string]interface{}{
+			"type":        "string",
+			"description": "Optional: 'uncategorized' or 'unapproved'",
+			"enum":        []string{"uncategorized", "unapproved"},
+		},
+		"since_date": map[string]interface{}{
+			"type":        "string",
+			"description": "Optional: only transactions on or after this date (YYYY-MM-DD)",
+		},
+		"until_date": map[string]interface{}{
+			"type":        "string",
+			"description": "Optional: only transactions on or before this date (YYYY-MM-DD)",
+		},
+		"payee_id": map[string]interface{}{
+			"type":        "string",
+			"description": "Optional: only transactions with this payee ID",
+		},
+		"payee": map[string]interface{}{
+			"type":        "string",
+			"description": "Optional: case-insensitive substring of the payee name",
+		},
+		"category_id": map[string]interface{}{
+			"type":        "string",
+			"description": "Optional: only transactions in this category (split transactions match if any split uses it)",
+		},
+		"category_group_id": map[string]interface{}{
+			"type":        "string",
+			"description": "Optional: only transactions in a category belonging to this category group",
+		},
+		"memo": map[string]interface{}{
+			"type":        "string",
+			"description": "Optional: case-insensitive substring of the memo",
+		},
+		"memo_regex": map[string]interface{}{
+			"type":        "string",
+			"description": "Optional: regular expression the memo must match (RE2 syntax)",
+		},
+		"min_amount": map[string]interface{}{
+			"type":        "number",
+			"description": "Optional: minimum signed amount in currency units (outflows are negative)",
+		},
+		"max_amount": map[string]interface{}{
+			"type":        "number",
+			"description": "Optional: maximum signed amount in currency units (e.g., -100 for outflows of $100 or more)",
+		},
+		"cleared": map[string]interface{}{
+			"type":        "string",
+			"description": "Optional: cleared status",
+			"enum":        []string{"cleared", "uncleared", "reconciled"},
+		},
+		"approved": map[string]interface{}{
+			"type":        "boolean",
+			"description": "Optional: only approved (true) or unapproved (false) transactions",
+		},
+		"flag_color": map[string]interface{}{
+			"type":        "string",
+			"description": "Optional: flag color",
+			"enum":        []string{"red", "orange", "yellow", "green", "blue", "purple"},
+		},
+	}
+}
+
+// parseTransactionFilter builds a transactionFilter from tool arguments
+func parseTransactionFilter(args map[string]interface{}) (*transactionFilter, error) {
+	filter := &transactionFilter{}
+
+	stringArg := func(name string) string {
+		value, _ := args[name].(string)
+		return value
+	}
+
+	filter.AccountID = stringArg("account_id")
+	filter.Type = stringArg("type")
+	filter.SinceDate = stringArg("since_date")
+	filter.UntilDate = stringArg("until_date")
+	filter.PayeeID = stringArg("payee_id")
+	filter.PayeeContains = strings.ToLower(stringArg("payee"))
+	filter.CategoryID = stringArg("category_id")
+	filter.CategoryGroupID = stringArg("category_group_id")
+	filter.MemoContains = strings.ToLower(stringArg("memo"))
+	filter.Cleared = stringArg("cleared")
+	filter.FlagColor = stringArg("flag_color")
+
+	if filter.Type != "" && filter.Type != "uncategorized" && filter.Type != "unapproved" {
+		return nil, fmt.Errorf("type must be 'uncategorized' or 'unapproved'")
+	}
+
+	if filter.SinceDate != "" {
+		if _, err := parseDate(filter.SinceDate); err != nil {
+			return nil, fmt.Errorf("since_date: %w", err)
+		}
+	}
+	if filter.UntilDate != "" {
+		if _, err := parseDate(filter.UntilDate); err != nil {
+			return nil, fmt.Errorf("until_date: %w", err)
+		}
+	}
+	if filter.SinceDate != "" && filter.UntilDate != "" && filter.UntilDate < filter.SinceDate {
+		return nil, fmt.Errorf("until_date must be after since_date")
+	}
+
+	if pattern := stringArg("memo_regex"); pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid memo_regex: %w", err)
+		}
+		filter.MemoRegex = re
+	}
+
+	if minAmount, ok := args["min_amount"].(float64); ok {
+		milliunits := ynab.FloatToMilliunits(minAmount)
+		filter.MinAmount = &milliunits
+	}
+	if maxAmount, ok := args["max_amount"].(float64); ok {
+		milliunits := ynab.FloatToMilliunits(maxAmount)
+		filter.MaxAmount = &milliunits
+	}
+	if filter.MinAmount != nil && filter.MaxAmount != nil && *filter.MinAmount > *filter.MaxAmount {
+		return nil, fmt.Errorf("min_amount must not be greater than max_amount")
+	}
+
+	if approved, ok := args["approved"].(bool); ok {
+		filter.Approved = &approved
+	}
+
+	return filter, nil
+}
+
+// matches reports whether a transaction satisfies every criterion in the filter
+func (f *transactionFilter) matches(tx ynab.Transaction) bool {
+	if tx.Deleted {
+		return false
+	}
+	if f.AccountID != "" && tx.AccountID != f.AccountID {
+		return false
+	}
+	if f.SinceDate != "" && tx.Date < f.SinceDate {
+		return false
+	}
+	if f.UntilDate != "" && tx.Date > f.UntilDate {
+		return false
+	}
+	if f.PayeeID != "" && tx.PayeeID != f.PayeeID {
+		return false
+	}
+	if f.PayeeContains != "" && !strings.Contains(strings.ToLower(tx.PayeeName), f.PayeeContains) {
+		return false
+	}
+	if f.CategoryID != "" && !transactionHasCategory(tx, func(id string) bool { return id == f.CategoryID }) {
+		return false
+	}
+	if f.CategoryGroupID != "" && !transactionHasCategory(tx, func(id string) bool { return f.categoryIDs[id] }) {
+		return false
+	}
+	if f.MemoContains != "" && !strings.Contains(strings.ToLower(tx.Memo), f.MemoContains) {
+		return false
+	}
+	if f.MemoRegex != nil && !f.MemoRegex.MatchString(tx.Memo) {
+		return false
+	}
+	if f.MinAmount != nil && tx.Amount < *f.MinAmount {
+		return false
+	}
+	if f.MaxAmount != nil && tx.Amount > *f.MaxAmount {
+		return false
+	}
+	if f.Cleared != "" && tx.Cleared != f.Cleared {
+		return false
+	}
+	if f.Approved != nil && tx.Approved != *f.Approved {
+		return false
+	}
+	if f.FlagColor != "" && tx.FlagColor != f.FlagColor {
+		return false
+	}
+	return true
+}
+
+// matchesType applies the type filter locally, for transactions that were
+// fetched without it being pushed down to YNAB
+func (f *transactionFilter) matchesType(tx ynab.Transaction) bool {
+	switch f.Type {
+	case "uncategorized":
+		return tx.CategoryID == "" && tx.TransferAccountID == "" && len(tx.Subtransactions) == 0
+	case "unapproved":
+		return !tx.Approved
+	}
+	return true
+}
+
+// hasSelection reports whether the call narrows the target set, so a bulk
+// tool never silently touches every transaction in the budget
+func (f *transactionFilter) hasSelection() bool {
+	return f.AccountID != "" || f.Type != "" || f.SinceDate != "" || f.UntilDate != "" ||
+		f.PayeeID != "" || f.PayeeContains != "" || f.CategoryID != "" || f.CategoryGroupID != "" ||
+		f.MemoContains != "" || f.MemoRegex != nil || f.MinAmount != nil || f.MaxAmount != nil ||
+		f.Cleared != "" || f.Approved != nil || f.FlagColor != ""
+}
+
+// transactionHasCategory reports whether the transaction or any of its splits
+// has a category accepted by match
+func transactionHasCategory(tx ynab.Transaction, match func(categoryID string) bool) bool {
+	if match(tx.CategoryID) {
+		return true
+	}
+	for _, sub := range tx.Subtransactions {
+		if !sub.Deleted && match(sub.CategoryID) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveCategoryGroup looks up the categories in CategoryGroupID so matches
+// can check them. It is a no-op without a category group filter.
+func (f *transactionFilter) resolveCategoryGroup(client *ynab.Client, budgetID string) error {
+	if f.CategoryGroupID == "" || f.categoryIDs != nil {
+		return nil
+	}
+	categoryGroups, err := client.ListCategories(budgetID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch categories: %w", err)
+	}
+	f.categoryIDs = make(map[string]bool)
+	for _, group := range categoryGroups {
+		if group.ID != f.CategoryGroupID {
+			continue
+		}
+		for _, category := range group.Categories {
+			f.categoryIDs[category.ID] = true
+		}
+	}
+	if len(f.categoryIDs) == 0 {
+		return fmt.Errorf("category group not found or empty: %s", f.CategoryGroupID)
+	}
+	return nil
+}
+
+// query returns the criteria YNAB can apply server-side
+func (f *transactionFilter) query() *ynab.TransactionQuery {
+	return &ynab.TransactionQuery{
+		SinceDate: f.SinceDate,
+		Type:      f.Type,
+	}
+}
+
+// streamFilterTransactions streams the transactions for filter's account and
+// date range from YNAB, calling fn with each one before any local matching
+func streamFilterTransactions(client *ynab.Client, budgetID string, filter *transactionFilter, fn func(tx ynab.Transaction) error) error {
+	if err := filter.resolveCategoryGroup(client, budgetID); err != nil {
+		return err
+	}
+	if filter.AccountID != "" {
+		return client.StreamAccountTransactions(budgetID, filter.AccountID, filter.query(), fn)
+	}
+	return client.StreamTransactions(budgetID, filter.query(), fn)
+}
+
+// fetchFilteredTransactions fetches transactions from YNAB and applies the filter.
+// since_date, type and account_id are pushed down to the API; everything else is
+// matched locally.
+func fetchFilteredTransactions(client *ynab.Client, budgetID string, filter *transactionFilter) ([]ynab.Transaction, error) {
+	matched := make([]ynab.Transaction, 0)
+	err := streamFilterTransactions(client, budgetID, filter, func(tx ynab.Transaction) error {
+		if filter.matches(tx) {
+			matched = append(matched, tx)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return matched, nil
+}
+
+// hasCategory reports whether the filter selects by category or category group
+func (f *transactionFilter) hasCategory() bool {
+	return f.CategoryID != "" || f.CategoryGroupID != ""
+}
+
+// matchingLines returns what the filter selects from a transaction. With a
+// category filter a split is expanded and only the lines in matching
+// categories are kept, so amount filters and totals cover just those lines
+// rather than the whole split.
+func (f *transactionFilter) matchingLines(tx ynab.Transaction) []ynab.Transaction {
+	if !f.hasCategory() || len(tx.Subtransactions) == 0 {
+		if f.matches(tx) {
+			return []ynab.Transaction{tx}
+		}
+		return nil
+	}
+	lines := make([]ynab.Transaction, 0, len(tx.Subtransactions))
+	for _, part := range flattenSplit(tx) {
+		if f.matches(part) {
+			lines = append(lines, part)
+		}
+	}
+	return lines
+}
+
+// transactionSummary is a compact JSON representation of a transaction
+type transactionSummary struct {
+	ID           string  `json:"id"`
+	Date         string  `json:"date"`
+	Amount       float64 `json:"amount"`
+	PayeeName    string  `json:"payee_name"`
+	CategoryName string  `json:"category_name,omitempty"`
+	AccountName  string  `json:"account_name"`
+	Memo         string  `json:"memo,omitempty"`
+	Cleared      string  `json:"cleared"`
+	Approved     bool    `json:"approved"`
+	FlagColor    string  `json:"flag_color,omitempty"`
+	// ParentID is set for a split line: the ID of the transaction it belongs to
+	ParentID string `json:"parent_transaction_id,omitempty"`
+}
+
+// newTransactionSummary converts a transaction into its compact JSON form
+func newTransactionSummary(tx ynab.Transaction) transactionSummary {
+	return transactionSummary{
+		ID:           tx.ID,
+		Date:         tx.Date,
+		Amount:       ynab.MilliunitsToFloat(tx.Amount),
+		PayeeName:    tx.PayeeName,
+		CategoryName: tx.CategoryName,
+		AccountName:  tx.AccountName,
+		Memo:         tx.Memo,
+		Cleared:      tx.Cleared,
+		Approved:     tx.Approved,
+		FlagColor:    tx.FlagColor,
+	}
+}
+
+// NewSearchTransactionsTool creates the search_transactions tool
+func NewSearchTransactionsTool(client *ynab.Client) ToolDefinition {
+	properties := transactionFilterProperties()
+	properties["budget_id"] = map[string]interface{}{
+		"type":        "string",
+		"description": "The ID of the budget",
+	}
+	properties["sort"] = map[string]interface{}{
+		"type":        "string",
+		"description": "Optional: 'date_desc' (default), 'date_asc', 'amount_desc' or 'amount_asc' (amount sorts use absolute values)",
+		"enum":        []string{sortDateDesc, sortDateAsc, sortAmountDesc, sortAmountAsc},
+	}
+	properties["limit"] = map[string]interface{}{
+		"type":        "number",
+		"description": fmt.Sprintf("Optional: maximum number of transactions to return (1-%d, default %d). Count and totals always cover every match.", maxTransactionPageSize, defaultTransactionPageSize),
+		"minimum":     1,
+		"maximum":     maxTransactionPageSize,
+	}
+
+	tool := mcp.Tool{
+		Name:        "search_transactions",
+		Description: "Search transactions with combinable filters: payee (ID or name substring), category or category group, memo substring or regex, amount range, date range, cleared status, approval, flag color and account. Returns matching transactions plus the total count and sum of all matches. With a category filter, split transactions are returned as their matching split lines (with parent_transaction_id), so amounts and totals only cover that category.",
+		InputSchema: mcp.ToolInputSchema{
+			Type:       "object",
+			Properties: properties,
+			Required:   []string{"budget_id"},
+		},
+	}
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		budgetID, ok := args["budget_id"].(string)
+		if !ok || budgetID == "" {
+			return mcp.NewToolResultError("budget_id is required"), nil
+		}
+
+		filter, err := parseTransactionFilter(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		sortBy := sortDateDesc
+		if sortArg, ok := args["sort"].(string); ok && sortArg != "" {
+			if !isValidTransactionSort(sortArg) {
+				return mcp.NewToolResultError(fmt.Sprintf("Invalid sort: %s", sortArg)), nil
+			}
+			sortBy = sortArg
+		}
+
+		limit := defaultTransactionPageSize
+		if limitFloat, ok := args["limit"].(float64); ok {
+			limit = int(limitFloat)
+			if limit < 1 || limit > maxTransactionPageSize {
+				return mcp.NewToolResultError(fmt.Sprintf("limit must be between 1 and %d", maxTransactionPageSize)), nil
+			}
+		}
+
+		// With a category filter, split transactions are returned as the matching lines
+		transactions := make([]ynab.Transaction, 0)
+		parents := make(map[string]string)
+		err = streamFilterTransactions(client, budgetID, filter, func(tx ynab.Transaction) error {
+			for _, line := range filter.matchingLines(tx) {
+				if line.ID != tx.ID {
+					parents[line.ID] = tx.ID
+				}
+				transactions = append(transactions, line)
+			}
+			return nil
+		})
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch transactions: %v", err)), nil
+		}
+
+		// Totals cover every match, not just the returned page
+		totalAmount := int64(0)
+		totalOutflow := int64(0)
+		totalInflow := int64(0)
+		for _, tx := range transactions {
+			totalAmount += tx.Amount
+			if tx.Amount < 0 {
+				totalOutflow += -tx.Amount
+			} else {
+				totalInflow += tx.Amount
+			}
+		}
+
+		matchCount := len(transactions)
+		sortTransactions(transactions, sortBy)
+		if len(transactions) > limit {
+			transactions = transactions[:limit]
+		}
+
+		summaries := make([]transactionSummary, 0, len(transactions))
+		for _, tx := range transactions {
+			summary := newTransactionSummary(tx)
+			summary.ParentID = parents[tx.ID]
+			summaries = append(summaries, summary)
+		}
+
+		// Build result
+		result := map[string]interface{}{
+			"transactions":  summaries,
+			"count":         matchCount,
+			"returned":      len(summaries),
+			"total_amount":  ynab.MilliunitsToFloat(totalAmount),
+			"total_outflow": ynab.MilliunitsToFloat(totalOutflow),
+			"total_inflow":  ynab.MilliunitsToFloat(totalInflow),
+		}
+
+		jsonResult, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to format result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonResult)), nil
+	}
+
+	return ToolDefinition{Tool: tool, Handler: handler}
+}
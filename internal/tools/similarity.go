@@ -0,0 +1,93 @@
+package tools
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Helper functions for comparing payee names
+
+// payeeNoiseWords are tokens bank imports add to payee names that carry no
+// information about the merchant
+var payeeNoiseWords = map[string]bool{
+	"pos":      true,
+	"debit":    true,
+	"credit":   true,
+	"purchase": true,
+	"card":     true,
+	"payment":  true,
+	"ach":      true,
+	"sq":       true,
+	"tst":      true,
+	"pp":       true,
+	"www":      true,
+	"com":      true,
+	"inc":      true,
+	"llc":      true,
+}
+
+// normalizePayeeName lowercases a payee name and strips digits, punctuation
+// and bank noise words so "SQ *BLUE BOTTLE #1234" and "Blue Bottle" compare equal
+func normalizePayeeName(name string) string {
+	cleaned := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) {
+			return unicode.ToLower(r)
+		}
+		return ' '
+	}, name)
+
+	words := make([]string, 0)
+	for _, word := range strings.Fields(cleaned) {
+		if payeeNoiseWords[word] {
+			continue
+		}
+		words = append(words, word)
+	}
+	return strings.Join(words, " ")
+}
+
+// payeeSimilarity returns a score between 0 and 1 for how alike two payee
+// names are, using the Dice coefficient over character bigrams of the
+// normalized names
+func payeeSimilarity(a, b string) float64 {
+	normA, normB := normalizePayeeName(a), normalizePayeeName(b)
+	if normA == "" || normB == "" {
+		return 0
+	}
+	if normA == normB {
+		return 1
+	}
+
+	bigramsA := bigrams(normA)
+	bigramsB := bigrams(normB)
+	if len(bigramsA) == 0 || len(bigramsB) == 0 {
+		return 0
+	}
+
+	overlap := 0
+	for bigram, countA := range bigramsA {
+		if countB, ok := bigramsB[bigram]; ok {
+			overlap += min(countA, countB)
+		}
+	}
+
+	totalA, totalB := 0, 0
+	for _, count := range bigramsA {
+		totalA += count
+	}
+	for _, count := range bigramsB {
+		totalB += count
+	}
+
+	return 2 * float64(overlap) / float64(totalA+totalB)
+}
+
+// bigrams counts the adjacent character pairs in s
+func bigrams(s string) map[string]int {
+	runes := []rune(s)
+	counts := make(map[string]int)
+	for i := 0; i+1 < len(runes); i++ {
+		counts[string(runes[i:i+2])]++
+	}
+	return counts
+}
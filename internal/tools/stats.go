@@ -0,0 +1,74 @@
+package tools
+
+import (
+	"math"
+	"sort"
+)
+
+// Statistical helper functions for analysis tools
+
+// median returns the median of values (0 for an empty slice)
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// mean returns the arithmetic mean of values (0 for an empty slice)
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	total := 0.0
+	for _, value := range values {
+		total += value
+	}
+	return total / float64(len(values))
+}
+
+// coefficientOfVariation returns the standard deviation divided by the mean,
+// a scale-free measure of how much values vary (0 when the mean is 0)
+func coefficientOfVariation(values []float64) float64 {
+	avg := mean(values)
+	if len(values) < 2 || avg == 0 {
+		return 0
+	}
+	variance := 0.0
+	for _, value := range values {
+		variance += (value - avg) * (value - avg)
+	}
+	variance /= float64(len(values))
+	return math.Sqrt(variance) / math.Abs(avg)
+}
+
+// medianAbsoluteDeviation returns the median of absolute deviations from the
+// median, a spread measure that a few outliers can't inflate
+func medianAbsoluteDeviation(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	center := median(values)
+	deviations := make([]float64, len(values))
+	for i, value := range values {
+		deviations[i] = math.Abs(value - center)
+	}
+	return median(deviations)
+}
+
+// robustZScore scores value against a baseline using the median and MAD.
+// The 0.6745 factor makes it comparable to a standard z-score for normal data.
+// Returns false when the baseline has no spread to measure against.
+func robustZScore(value float64, baseline []float64) (float64, bool) {
+	mad := medianAbsoluteDeviation(baseline)
+	if mad == 0 {
+		return 0, false
+	}
+	return 0.6745 * (value - median(baseline)) / mad, true
+}
@@ -0,0 +1,148 @@
+package tools
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jeff-french/ynab-mcp-server/internal/ynab"
+)
+
+// progressInterval is how many transactions are read between progress reports
+// when the date range, and so the percentage done, isn't known
+const progressInterval = 100
+
+// progressFunc reports how far a long-running operation has got. total is 0
+// when the amount of work isn't known up front.
+type progressFunc func(progress, total float64, message string)
+
+// logProgress reports progress to the debug log
+func logProgress(progress, total float64, message string) {
+	slog.Debug("Progress", "progress", progress, "total", total, "message", message)
+}
+
+// dateProgress measures progress through the filter's date range in days,
+// from the dates of the transactions read so far
+type dateProgress struct {
+	since    time.Time
+	days     float64
+	done     float64
+	reported float64
+}
+
+// newDateProgress returns a tracker for the filter's range, which runs to
+// today when the filter has no until date. Without a since date the total is unknown.
+func newDateProgress(filter *transactionFilter) *dateProgress {
+	tracker := &dateProgress{}
+	since, err := parseDate(filter.SinceDate)
+	if err != nil {
+		return tracker
+	}
+	until := time.Now()
+	if parsed, err := parseDate(filter.UntilDate); err == nil {
+		until = parsed
+	}
+	tracker.since = since
+	tracker.days = daysBetween(since, until) + 1
+	return tracker
+}
+
+// advance records a transaction date; progress never moves backwards
+func (p *dateProgress) advance(date string) {
+	if p.days == 0 {
+		return
+	}
+	txDate, err := parseDate(date)
+	if err != nil {
+		return
+	}
+	p.done = min(max(p.done, daysBetween(p.since, txDate)+1), p.days)
+}
+
+// due reports whether progress has moved far enough to report again: another
+// percent of the date range (at least a day), or progressInterval more
+// transactions when the range isn't known
+func (p *dateProgress) due(read int) bool {
+	if p.days == 0 {
+		return read%progressInterval == 0
+	}
+	if p.done-p.reported < max(p.days/100, 1) {
+		return false
+	}
+	p.reported = p.done
+	return true
+}
+
+// position returns the progress and total to report after read transactions.
+// Without a known range, progress is the number of transactions read.
+func (p *dateProgress) position(read int) (float64, float64) {
+	if p.days == 0 {
+		return float64(read), 0
+	}
+	return p.done, p.days
+}
+
+// streamAggregationLines streams the transactions selected by filter from
+// YNAB, expands splits, drops the transfers mode doesn't count, and calls fn
+// with every line that still matches the filter. Transactions are decoded and
+// handled one at a time, so memory use depends on what fn keeps rather than
+// on the length of the date range. Returns the number of transactions read.
+func streamAggregationLines(client *ynab.Client, budgetID string, filter *transactionFilter, onBudget map[string]bool, mode string, progress progressFunc, fn func(line ynab.Transaction)) (int, error) {
+	return streamAggregationTransactions(client, budgetID, filter, onBudget, mode, progress, func(_ ynab.Transaction, lines []ynab.Transaction) {
+		for _, line := range lines {
+			fn(line)
+		}
+	})
+}
+
+// streamAggregationTransactions is streamAggregationLines for callers that
+// also need the transaction a line came from, such as the parent of a split.
+// fn is called once per matching transaction with its counted lines.
+func streamAggregationTransactions(client *ynab.Client, budgetID string, filter *transactionFilter, onBudget map[string]bool, mode string, progress progressFunc, fn func(tx ynab.Transaction, lines []ynab.Transaction)) (int, error) {
+	if err := filter.resolveCategoryGroup(client, budgetID); err != nil {
+		return 0, err
+	}
+	if progress == nil {
+		progress = logProgress
+	}
+
+	tracker := newDateProgress(filter)
+	progress(0, tracker.days, "Fetching transactions")
+	read := 0
+	err := streamFilterTransactions(client, budgetID, filter, func(tx ynab.Transaction) error {
+		read++
+		tracker.advance(tx.Date)
+		if tracker.due(read) {
+			done, total := tracker.position(read)
+			progress(done, total, fmt.Sprintf("Aggregated %d transactions (through %s)", read, tx.Date))
+		}
+
+		if !filter.matches(tx) {
+			return nil
+		}
+		lines := make([]ynab.Transaction, 0, 1)
+		for _, part := range flattenSplit(tx) {
+			if isTransfer(part) && !countsTransfer(part, onBudget, mode) {
+				continue
+			}
+			// Re-check per line so a category filter doesn't pull in a split's other lines
+			if filter.matches(part) {
+				lines = append(lines, part)
+			}
+		}
+		if len(lines) > 0 {
+			fn(tx, lines)
+		}
+		return nil
+	})
+	if err != nil {
+		return read, fmt.Errorf("failed to fetch transactions: %w", err)
+	}
+
+	total := tracker.days
+	if total == 0 {
+		total = float64(read)
+	}
+	progress(total, total, fmt.Sprintf("Aggregated %d transactions", read))
+	return read, nil
+}
@@ -0,0 +1,303 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/jeff-french/ynab-mcp-server/internal/ynab"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	defaultSubscriptionMonths         = 12
+	defaultSubscriptionMinOccurrences = 3
+	maxSubscriptionAmountVariation    = 0.35
+	minCadenceRegularity              = 0.7
+)
+
+// priceChange records a change in a recurring charge's amount
+type priceChange struct {
+	Date          string  `json:"date"`
+	From          float64 `json:"from"`
+	To            float64 `json:"to"`
+	ChangePercent float64 `json:"change_percent"`
+}
+
+// recurringCharge describes a detected subscription or other recurring charge
+type recurringCharge struct {
+	PayeeID           string        `json:"payee_id"`
+	PayeeName         string        `json:"payee_name"`
+	CategoryName      string        `json:"category_name,omitempty"`
+	Cadence           string        `json:"cadence"`
+	Occurrences       int           `json:"occurrences"`
+	FirstCharge       string        `json:"first_charge"`
+	LastCharge        string        `json:"last_charge"`
+	NextExpected      string        `json:"next_expected"`
+	LatestAmount      float64       `json:"latest_amount"`
+	MonthlyEquivalent float64       `json:"monthly_equivalent"`
+	FixedAmount       bool          `json:"fixed_amount"`
+	PriceChanges      []priceChange `json:"price_changes,omitempty"`
+	Active            bool          `json:"active"`
+	ScheduledInYNAB   bool          `json:"scheduled_in_ynab"`
+	Regularity        float64       `json:"regularity"`
+}
+
+// detectRecurringCharge analyzes one payee's outflows (sorted by date) and
+// returns the recurring charge if their cadence is regular enough
+func detectRecurringCharge(charges []ynab.Transaction, minOccurrences int, today time.Time) (*recurringCharge, bool) {
+	if len(charges) < 2 {
+		return nil, false
+	}
+
+	dates := make([]time.Time, 0, len(charges))
+	amounts := make([]float64, 0, len(charges))
+	for _, tx := range charges {
+		date, err := parseDate(tx.Date)
+		if err != nil {
+			return nil, false
+		}
+		dates = append(dates, date)
+		amounts = append(amounts, -ynab.MilliunitsToFloat(tx.Amount))
+	}
+
+	intervals := make([]float64, 0, len(dates)-1)
+	for i := 1; i < len(dates); i++ {
+		intervals = append(intervals, daysBetween(dates[i-1], dates[i]))
+	}
+
+	c, ok := classifyInterval(median(intervals))
+	if !ok {
+		return nil, false
+	}
+
+	// Annual charges only get a couple of data points in a typical window
+	required := minOccurrences
+	if c.months >= 6 {
+		required = 2
+	}
+	if len(dates) < required {
+		return nil, false
+	}
+
+	regular := 0
+	for _, interval := range intervals {
+		if interval >= c.MinDays*0.8 && interval <= c.MaxDays*1.2 {
+			regular++
+		}
+	}
+	regularity := float64(regular) / float64(len(intervals))
+	if regularity < minCadenceRegularity {
+		return nil, false
+	}
+
+	variation := coefficientOfVariation(amounts)
+	if variation > maxSubscriptionAmountVariation {
+		return nil, false
+	}
+
+	last := charges[len(charges)-1]
+	lastDate := dates[len(dates)-1]
+	nextExpected := c.next(lastDate)
+	latestAmount := amounts[len(amounts)-1]
+
+	charge := &recurringCharge{
+		PayeeID:           last.PayeeID,
+		PayeeName:         last.PayeeName,
+		CategoryName:      last.CategoryName,
+		Cadence:           c.Name,
+		Occurrences:       len(dates),
+		FirstCharge:       charges[0].Date,
+		LastCharge:        last.Date,
+		NextExpected:      nextExpected.Format("2006-01-02"),
+		LatestAmount:      latestAmount,
+		MonthlyEquivalent: roundTo(latestAmount*c.PerMonth, 2),
+		FixedAmount:       variation < 0.05,
+		// Lapsed if more than half a cycle has passed since the expected charge
+		Active:     daysBetween(nextExpected, today) <= c.nominalDays()/2,
+		Regularity: roundTo(regularity, 2),
+	}
+
+	// Price changes are only meaningful for otherwise fixed amounts
+	if variation < 0.2 {
+		for i := 1; i < len(amounts); i++ {
+			if math.Abs(amounts[i]-amounts[i-1]) < 0.01 {
+				continue
+			}
+			charge.PriceChanges = append(charge.PriceChanges, priceChange{
+				Date:          charges[i].Date,
+				From:          amounts[i-1],
+				To:            amounts[i],
+				ChangePercent: roundTo((amounts[i]-amounts[i-1])/amounts[i-1]*100, 1),
+			})
+		}
+	}
+
+	return charge, true
+}
+
+// isCoveredBySchedule reports whether a scheduled transaction exists for the payee
+func isCoveredBySchedule(charge *recurringCharge, scheduled []ynab.ScheduledTransaction) bool {
+	for _, st := range scheduled {
+		if st.Deleted {
+			continue
+		}
+		if st.PayeeID != "" && st.PayeeID == charge.PayeeID {
+			return true
+		}
+		if payeeSimilarity(st.PayeeName, charge.PayeeName) >= importNameSimilarityThreshold {
+			return true
+		}
+	}
+	return false
+}
+
+// NewDetectSubscriptionsTool creates the detect_subscriptions tool
+func NewDetectSubscriptionsTool(client *ynab.Client) ToolDefinition {
+	tool := mcp.Tool{
+		Name:        "detect_subscriptions",
+		Description: "Detect subscriptions and other recurring charges (weekly, biweekly, monthly, quarterly, annual) from payee cadence and amounts. Estimates the next charge date and monthly-equivalent cost, flags price changes, and notes which recurring charges have no scheduled transaction in YNAB.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"budget_id": map[string]interface{}{
+					"type":        "string",
+					"description": "The ID of the budget",
+				},
+				"num_months": map[string]interface{}{
+					"type":        "number",
+					"description": fmt.Sprintf("Optional: months of history to analyze (at least 3, default %d)", defaultSubscriptionMonths),
+					"minimum":     3,
+				},
+				"min_occurrences": map[string]interface{}{
+					"type":        "number",
+					"description": fmt.Sprintf("Optional: minimum charges needed to call something recurring (default %d; annual and semiannual charges need 2)", defaultSubscriptionMinOccurrences),
+					"minimum":     2,
+				},
+				"include_inactive": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Optional: include recurring charges that appear to have stopped (default false)",
+				},
+			},
+			Required: []string{"budget_id"},
+		},
+	}
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		budgetID, ok := args["budget_id"].(string)
+		if !ok || budgetID == "" {
+			return mcp.NewToolResultError("budget_id is required"), nil
+		}
+
+		numMonths := defaultSubscriptionMonths
+		if monthsFloat, ok := args["num_months"].(float64); ok {
+			numMonths = int(monthsFloat)
+			if numMonths < 3 {
+				return mcp.NewToolResultError("num_months must be at least 3"), nil
+			}
+		}
+
+		minOccurrences := defaultSubscriptionMinOccurrences
+		if occurrencesFloat, ok := args["min_occurrences"].(float64); ok {
+			minOccurrences = int(occurrencesFloat)
+			if minOccurrences < 2 {
+				return mcp.NewToolResultError("min_occurrences must be at least 2"), nil
+			}
+		}
+
+		includeInactive, _ := args["include_inactive"].(bool)
+
+		months := getLastNMonths(numMonths)
+		sinceDate := months[0] + "-01"
+
+		scheduled, err := client.ListScheduledTransactions(budgetID)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch scheduled transactions: %v", err)), nil
+		}
+
+		// Group outflows by payee, keeping split transactions whole
+		byPayee := make(map[string][]ynab.Transaction)
+		filter := &transactionFilter{SinceDate: sinceDate}
+		_, err = streamAggregationTransactions(client, budgetID, filter, nil, transferHandlingExclude, newProgressReporter(ctx, request), func(tx ynab.Transaction, _ []ynab.Transaction) {
+			if !isAnalyzable(tx) || tx.Amount >= 0 {
+				return
+			}
+			key := tx.PayeeID
+			if key == "" {
+				key = normalizePayeeName(tx.PayeeName)
+			}
+			if key == "" {
+				return
+			}
+			byPayee[key] = append(byPayee[key], tx)
+		})
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		today := time.Now()
+		charges := make([]*recurringCharge, 0)
+		for _, payeeTxs := range byPayee {
+			sort.SliceStable(payeeTxs, func(i, j int) bool {
+				return payeeTxs[i].Date < payeeTxs[j].Date
+			})
+
+			charge, found := detectRecurringCharge(payeeTxs, minOccurrences, today)
+			if !found || (!charge.Active && !includeInactive) {
+				continue
+			}
+			charge.ScheduledInYNAB = isCoveredBySchedule(charge, scheduled)
+			charges = append(charges, charge)
+		}
+
+		sort.Slice(charges, func(i, j int) bool {
+			return charges[i].MonthlyEquivalent > charges[j].MonthlyEquivalent
+		})
+
+		totalMonthly := 0.0
+		unscheduled := make([]string, 0)
+		withPriceChanges := 0
+		for _, charge := range charges {
+			if charge.Active {
+				totalMonthly += charge.MonthlyEquivalent
+			}
+			if !charge.ScheduledInYNAB {
+				unscheduled = append(unscheduled, charge.PayeeName)
+			}
+			if len(charge.PriceChanges) > 0 {
+				withPriceChanges++
+			}
+		}
+
+		// Build result
+		result := map[string]interface{}{
+			"recurring_charges":        charges,
+			"count":                    len(charges),
+			"total_monthly_equivalent": roundTo(totalMonthly, 2),
+			"total_annual_equivalent":  roundTo(totalMonthly*12, 2),
+			"not_scheduled_in_ynab":    unscheduled,
+			"with_price_changes":       withPriceChanges,
+			"analysis_period": map[string]string{
+				"since": sinceDate,
+				"until": today.Format("2006-01-02"),
+			},
+		}
+
+		jsonResult, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to format result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonResult)), nil
+	}
+
+	return ToolDefinition{Tool: tool, Handler: handler}
+}
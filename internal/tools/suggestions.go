@@ -0,0 +1,401 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jeff-french/ynab-mcp-server/internal/ynab"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	defaultSuggestionHistoryMonths = 6
+	defaultSuggestionThreshold     = 0.75
+	importNameSimilarityThreshold  = 0.8
+)
+
+// categoryHistory holds how often, and for what amounts, a category was used
+type categoryHistory struct {
+	CategoryID   string
+	CategoryName string
+	Weight       float64 // number of transactions, scaled by name similarity
+	MinAmount    int64
+	MaxAmount    int64
+}
+
+// payeeCategoryHistory is the category distribution for one payee
+type payeeCategoryHistory struct {
+	Categories map[string]*categoryHistory
+	Count      int
+}
+
+// add records a categorized transaction in the history
+func (h *payeeCategoryHistory) add(categoryID, categoryName string, amount int64) {
+	entry, exists := h.Categories[categoryID]
+	if !exists {
+		entry = &categoryHistory{
+			CategoryID:   categoryID,
+			CategoryName: categoryName,
+			MinAmount:    amount,
+			MaxAmount:    amount,
+		}
+		h.Categories[categoryID] = entry
+	}
+	entry.Weight++
+	entry.MinAmount = min(entry.MinAmount, amount)
+	entry.MaxAmount = max(entry.MaxAmount, amount)
+	h.Count++
+}
+
+// categoryAlternative is a candidate category with its share of the history
+type categoryAlternative struct {
+	CategoryID   string  `json:"category_id"`
+	CategoryName string  `json:"category_name"`
+	Share        float64 `json:"share"`
+}
+
+// categorySuggestion is the proposed category for an uncategorized transaction
+type categorySuggestion struct {
+	TransactionID         string                `json:"transaction_id"`
+	Date                  string                `json:"date"`
+	PayeeName             string                `json:"payee_name"`
+	Amount                float64               `json:"amount"`
+	SuggestedCategoryID   string                `json:"suggested_category_id"`
+	SuggestedCategoryName string                `json:"suggested_category_name"`
+	Confidence            float64               `json:"confidence"`
+	Basis                 string                `json:"basis"`
+	HistoryCount          int                   `json:"history_count"`
+	AmountInRange         bool                  `json:"amount_in_historical_range"`
+	Alternatives          []categoryAlternative `json:"alternatives,omitempty"`
+	Applied               bool                  `json:"applied"`
+
+	transaction ynab.Transaction
+}
+
+// payeeHistories indexes categorized, analyzable transactions by payee ID
+// and by normalized import payee name. Split transactions contribute each
+// subtransaction under its own category.
+type payeeHistories struct {
+	byPayee      map[string]*payeeCategoryHistory
+	byImportName map[string]*payeeCategoryHistory
+}
+
+// newPayeeHistories returns an empty index
+func newPayeeHistories() *payeeHistories {
+	return &payeeHistories{
+		byPayee:      make(map[string]*payeeCategoryHistory),
+		byImportName: make(map[string]*payeeCategoryHistory),
+	}
+}
+
+// record adds one categorized amount under key
+func (h *payeeHistories) record(index map[string]*payeeCategoryHistory, key, categoryID, categoryName string, amount int64) {
+	if key == "" || categoryID == "" {
+		return
+	}
+	history, exists := index[key]
+	if !exists {
+		history = &payeeCategoryHistory{Categories: make(map[string]*categoryHistory)}
+		index[key] = history
+	}
+	history.add(categoryID, categoryName, amount)
+}
+
+// add indexes a transaction, skipping transfers and deleted transactions
+func (h *payeeHistories) add(tx ynab.Transaction) {
+	if !isAnalyzable(tx) {
+		return
+	}
+
+	importName := normalizePayeeName(importPayeeName(tx))
+
+	if len(tx.Subtransactions) > 0 {
+		for _, sub := range tx.Subtransactions {
+			if sub.Deleted || sub.TransferAccountID != "" {
+				continue
+			}
+			payeeID := sub.PayeeID
+			if payeeID == "" {
+				payeeID = tx.PayeeID
+			}
+			h.record(h.byPayee, payeeID, sub.CategoryID, sub.CategoryName, sub.Amount)
+			h.record(h.byImportName, importName, sub.CategoryID, sub.CategoryName, sub.Amount)
+		}
+		return
+	}
+
+	h.record(h.byPayee, tx.PayeeID, tx.CategoryID, tx.CategoryName, tx.Amount)
+	h.record(h.byImportName, importName, tx.CategoryID, tx.CategoryName, tx.Amount)
+}
+
+// importPayeeName returns the most original payee text YNAB has for a transaction
+func importPayeeName(tx ynab.Transaction) string {
+	if tx.ImportPayeeNameOriginal != "" {
+		return tx.ImportPayeeNameOriginal
+	}
+	return tx.ImportPayeeName
+}
+
+// suggestCategory proposes a category for a transaction from payee history.
+// The same payee's history is used when available; otherwise histories of
+// similar imported payee names are combined, weighted by similarity.
+// Confidence = category share × amount fit × n/(n+1), where n is the number of
+// historical transactions, so suggestions backed by little history score lower.
+func suggestCategory(tx ynab.Transaction, byPayee, byImportName map[string]*payeeCategoryHistory) (categorySuggestion, bool) {
+	suggestion := categorySuggestion{
+		TransactionID: tx.ID,
+		Date:          tx.Date,
+		PayeeName:     tx.PayeeName,
+		Amount:        ynab.MilliunitsToFloat(tx.Amount),
+		transaction:   tx,
+	}
+
+	combined := make(map[string]*categoryHistory)
+	historyCount := 0
+
+	if history, ok := byPayee[tx.PayeeID]; ok && tx.PayeeID != "" {
+		suggestion.Basis = "payee history"
+		for id, entry := range history.Categories {
+			copied := *entry
+			combined[id] = &copied
+		}
+		historyCount = history.Count
+	} else if name := importPayeeName(tx); name != "" {
+		suggestion.Basis = "similar imported payee names"
+		for key, history := range byImportName {
+			similarity := payeeSimilarity(name, key)
+			if similarity < importNameSimilarityThreshold {
+				continue
+			}
+			for id, entry := range history.Categories {
+				existing, exists := combined[id]
+				if !exists {
+					existing = &categoryHistory{
+						CategoryID:   entry.CategoryID,
+						CategoryName: entry.CategoryName,
+						MinAmount:    entry.MinAmount,
+						MaxAmount:    entry.MaxAmount,
+					}
+					combined[id] = existing
+				}
+				existing.Weight += entry.Weight * similarity
+				existing.MinAmount = min(existing.MinAmount, entry.MinAmount)
+				existing.MaxAmount = max(existing.MaxAmount, entry.MaxAmount)
+			}
+			historyCount += history.Count
+		}
+	}
+
+	if len(combined) == 0 {
+		return suggestion, false
+	}
+
+	totalWeight := 0.0
+	alternatives := make([]*categoryHistory, 0, len(combined))
+	for _, entry := range combined {
+		totalWeight += entry.Weight
+		alternatives = append(alternatives, entry)
+	}
+	sort.Slice(alternatives, func(i, j int) bool {
+		if alternatives[i].Weight != alternatives[j].Weight {
+			return alternatives[i].Weight > alternatives[j].Weight
+		}
+		return alternatives[i].CategoryName < alternatives[j].CategoryName
+	})
+
+	best := alternatives[0]
+	share := best.Weight / totalWeight
+	suggestion.AmountInRange = tx.Amount >= best.MinAmount && tx.Amount <= best.MaxAmount
+
+	amountFit := 1.0
+	if !suggestion.AmountInRange {
+		amountFit = 0.8
+	}
+	sampleFit := float64(historyCount) / float64(historyCount+1)
+
+	suggestion.SuggestedCategoryID = best.CategoryID
+	suggestion.SuggestedCategoryName = best.CategoryName
+	suggestion.Confidence = roundTo(share*amountFit*sampleFit, 2)
+	suggestion.HistoryCount = historyCount
+
+	for _, alt := range alternatives[1:min(len(alternatives), 4)] {
+		suggestion.Alternatives = append(suggestion.Alternatives, categoryAlternative{
+			CategoryID:   alt.CategoryID,
+			CategoryName: alt.CategoryName,
+			Share:        roundTo(alt.Weight/totalWeight, 2),
+		})
+	}
+
+	return suggestion, true
+}
+
+// roundTo rounds a value to the given number of decimal places
+func roundTo(value float64, places int) float64 {
+	scale := 1.0
+	for i := 0; i < places; i++ {
+		scale *= 10
+	}
+	if value < 0 {
+		return float64(int64(value*scale-0.5)) / scale
+	}
+	return float64(int64(value*scale+0.5)) / scale
+}
+
+// NewSuggestCategoriesTool creates the suggest_categories tool
+func NewSuggestCategoriesTool(client *ynab.Client) ToolDefinition {
+	tool := mcp.Tool{
+		Name:        "suggest_categories",
+		Description: "Suggest categories for uncategorized transactions based on how the same payee (or similar imported payee names) was categorized historically. Each suggestion has a confidence score between 0 and 1. Set apply=true to categorize every transaction whose confidence meets min_confidence in a single bulk update.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"budget_id": map[string]interface{}{
+					"type":        "string",
+					"description": "The ID of the budget",
+				},
+				"history_months": map[string]interface{}{
+					"type":        "number",
+					"description": fmt.Sprintf("Optional: months of history to learn from (at least 1, default %d)", defaultSuggestionHistoryMonths),
+					"minimum":     1,
+				},
+				"account_id": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional: only suggest for uncategorized transactions in this account",
+				},
+				"apply": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Optional: apply suggestions at or above min_confidence (default false)",
+				},
+				"min_confidence": map[string]interface{}{
+					"type":        "number",
+					"description": fmt.Sprintf("Optional: minimum confidence to apply a suggestion (0-1, default %.2f)", defaultSuggestionThreshold),
+					"minimum":     0,
+					"maximum":     1,
+				},
+			},
+			Required: []string{"budget_id"},
+		},
+	}
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		budgetID, ok := args["budget_id"].(string)
+		if !ok || budgetID == "" {
+			return mcp.NewToolResultError("budget_id is required"), nil
+		}
+
+		historyMonths := defaultSuggestionHistoryMonths
+		if monthsFloat, ok := args["history_months"].(float64); ok {
+			historyMonths = int(monthsFloat)
+			if historyMonths < 1 {
+				return mcp.NewToolResultError("history_months must be at least 1"), nil
+			}
+		}
+
+		threshold := defaultSuggestionThreshold
+		if thresholdFloat, ok := args["min_confidence"].(float64); ok {
+			if thresholdFloat < 0 || thresholdFloat > 1 {
+				return mcp.NewToolResultError("min_confidence must be between 0 and 1"), nil
+			}
+			threshold = thresholdFloat
+		}
+
+		apply, _ := args["apply"].(bool)
+		accountID, _ := args["account_id"].(string)
+
+		// Reading the uncategorized transactions and the history are the first
+		// two steps; applying the suggestions is the last
+		progress := newProgressReporter(ctx, request)
+		historyEnd := 100.0
+		if apply {
+			historyEnd = 80
+		}
+
+		uncategorized := make([]ynab.Transaction, 0)
+		uncategorizedFilter := &transactionFilter{Type: "uncategorized", AccountID: accountID}
+		_, err := streamAggregationTransactions(client, budgetID, uncategorizedFilter, nil, transferHandlingExclude, progressPhase(progress, 0, 20), func(tx ynab.Transaction, _ []ynab.Transaction) {
+			uncategorized = append(uncategorized, tx)
+		})
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch uncategorized transactions: %v", err)), nil
+		}
+
+		histories := newPayeeHistories()
+		historyFilter := &transactionFilter{SinceDate: time.Now().AddDate(0, -historyMonths, 0).Format("2006-01-02")}
+		_, err = streamAggregationTransactions(client, budgetID, historyFilter, nil, transferHandlingAll, progressPhase(progress, 20, historyEnd-20), func(tx ynab.Transaction, _ []ynab.Transaction) {
+			histories.add(tx)
+		})
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch transaction history: %v", err)), nil
+		}
+
+		suggestions := make([]categorySuggestion, 0)
+		noHistory := make([]transactionSummary, 0)
+		for _, tx := range uncategorized {
+			if !isAnalyzable(tx) || len(tx.Subtransactions) > 0 {
+				continue
+			}
+			suggestion, found := suggestCategory(tx, histories.byPayee, histories.byImportName)
+			if !found {
+				noHistory = append(noHistory, newTransactionSummary(tx))
+				continue
+			}
+			suggestions = append(suggestions, suggestion)
+		}
+
+		sort.SliceStable(suggestions, func(i, j int) bool {
+			return suggestions[i].Confidence > suggestions[j].Confidence
+		})
+
+		applied := 0
+		if apply {
+			plans := make([]plannedUpdate, 0)
+			for i := range suggestions {
+				if suggestions[i].Confidence < threshold {
+					continue
+				}
+				plans = append(plans, plannedUpdate{
+					Transaction: suggestions[i].transaction,
+					Update: ynab.BulkTransactionUpdate{
+						ID:         suggestions[i].TransactionID,
+						CategoryID: suggestions[i].SuggestedCategoryID,
+					},
+				})
+				suggestions[i].Applied = true
+			}
+
+			if len(plans) > 0 {
+				if _, err := applyPlannedUpdates(client, budgetID, plans, progressPhase(progress, historyEnd, 100-historyEnd)); err != nil {
+					return mcp.NewToolResultError(fmt.Sprintf("Failed to apply suggestions: %v", err)), nil
+				}
+			}
+			applied = len(plans)
+		}
+
+		// Build result
+		result := map[string]interface{}{
+			"suggestions":              suggestions,
+			"without_history":          noHistory,
+			"history_months":           historyMonths,
+			"min_confidence":           threshold,
+			"applied_count":            applied,
+			"uncategorized_considered": len(suggestions) + len(noHistory),
+		}
+
+		jsonResult, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to format result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonResult)), nil
+	}
+
+	return ToolDefinition{Tool: tool, Handler: handler}
+}
@@ -0,0 +1,360 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jeff-french/ynab-mcp-server/internal/ynab"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// tagPattern matches #hashtags that start a word, so "#vacation2026" counts
+// but "item#3" and "&#39;" do not
+var tagPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_&#])#([\p{L}\p{N}_][\p{L}\p{N}_-]*)`)
+
+// parseTags extracts lowercased, de-duplicated hashtags (without the #) from a memo
+func parseTags(memo string) []string {
+	if !strings.Contains(memo, "#") {
+		return nil
+	}
+	seen := make(map[string]bool)
+	tags := make([]string, 0)
+	for _, match := range tagPattern.FindAllStringSubmatch(memo, -1) {
+		tag := strings.ToLower(strings.TrimRight(match[1], "-"))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// normalizeTag lowercases a tag and strips a leading #
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+}
+
+// lineTags returns the tags that apply to a line of tx. Tags in a split's
+// parent memo apply to every line.
+func lineTags(tx, line ynab.Transaction) []string {
+	tags := parseTags(tx.Memo)
+	if line.Memo != tx.Memo {
+		tags = mergeTags(tags, parseTags(line.Memo))
+	}
+	return tags
+}
+
+// mergeTags returns the union of two tag lists, preserving order
+func mergeTags(a, b []string) []string {
+	merged := append([]string(nil), a...)
+	for _, tag := range b {
+		found := false
+		for _, existing := range merged {
+			if existing == tag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, tag)
+		}
+	}
+	return merged
+}
+
+// tagSummary holds aggregated data for a tag
+type tagSummary struct {
+	Tag              string             `json:"tag"`
+	TotalOutflow     float64            `json:"total_outflow"`
+	TotalInflow      float64            `json:"total_inflow"`
+	Net              float64            `json:"net"`
+	TransactionCount int                `json:"transaction_count"`
+	FirstUsed        string             `json:"first_used"`
+	LastUsed         string             `json:"last_used"`
+	Categories       []*categorySummary `json:"categories,omitempty"`
+}
+
+// tagAggregator sums tagged lines per tag as they stream in. A line with
+// several tags counts toward each of them, so tag totals are not additive.
+type tagAggregator struct {
+	summaries  map[string]*tagSummary
+	categories map[string]*aggregator // nil unless categories are broken down
+}
+
+// newTagAggregator returns a tagAggregator, optionally breaking each tag down by category
+func newTagAggregator(includeCategories bool) *tagAggregator {
+	agg := &tagAggregator{summaries: make(map[string]*tagSummary)}
+	if includeCategories {
+		agg.categories = make(map[string]*aggregator)
+	}
+	return agg
+}
+
+// add sums a line into the summary of each of its tags
+func (a *tagAggregator) add(tx ynab.Transaction, tags []string) {
+	amount := ynab.MilliunitsToFloat(tx.Amount)
+	for _, tag := range tags {
+		summary, exists := a.summaries[tag]
+		if !exists {
+			summary = &tagSummary{Tag: tag, FirstUsed: tx.Date, LastUsed: tx.Date}
+			a.summaries[tag] = summary
+		}
+		if amount < 0 {
+			summary.TotalOutflow += -amount // Store as positive
+		} else {
+			summary.TotalInflow += amount
+		}
+		summary.Net += amount
+		summary.TransactionCount++
+		if tx.Date < summary.FirstUsed {
+			summary.FirstUsed = tx.Date
+		}
+		if tx.Date > summary.LastUsed {
+			summary.LastUsed = tx.Date
+		}
+		if a.categories != nil {
+			if a.categories[tag] == nil {
+				a.categories[tag] = newAggregator([]groupingKey{categoryKey})
+			}
+			a.categories[tag].add(tx)
+		}
+	}
+}
+
+// result returns the tag summaries with their category breakdowns
+func (a *tagAggregator) result() map[string]*tagSummary {
+	for tag, agg := range a.categories {
+		categories := make([]*categorySummary, 0)
+		for _, category := range categorySummaries(agg.groups) {
+			categories = append(categories, category)
+		}
+		sort.Slice(categories, func(i, j int) bool {
+			return categories[i].TotalOutflow > categories[j].TotalOutflow
+		})
+		a.summaries[tag].Categories = categories
+	}
+	return a.summaries
+}
+
+// sortedTagSummaries rounds summaries and orders them by rank, highest first
+func sortedTagSummaries(summaries map[string]*tagSummary, rank func(summary *tagSummary) float64) []*tagSummary {
+	sorted := make([]*tagSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		summary.TotalOutflow = roundTo(summary.TotalOutflow, 2)
+		summary.TotalInflow = roundTo(summary.TotalInflow, 2)
+		summary.Net = roundTo(summary.Net, 2)
+		sorted = append(sorted, summary)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if rank(sorted[i]) != rank(sorted[j]) {
+			return rank(sorted[i]) > rank(sorted[j])
+		}
+		return sorted[i].Tag < sorted[j].Tag
+	})
+	return sorted
+}
+
+// streamTagSummaries streams the filtered transactions from YNAB and sums
+// their tagged lines per tag, after splits are expanded and the transfers
+// mode doesn't count are dropped
+func streamTagSummaries(client *ynab.Client, budgetID string, filter *transactionFilter, mode string, includeCategories bool, progress progressFunc) (map[string]*tagSummary, error) {
+	onBudget, err := onBudgetAccounts(client, budgetID, mode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch accounts: %w", err)
+	}
+
+	agg := newTagAggregator(includeCategories)
+	_, err = streamAggregationTransactions(client, budgetID, filter, onBudget, mode, progress, func(tx ynab.Transaction, lines []ynab.Transaction) {
+		for _, line := range lines {
+			if tags := lineTags(tx, line); len(tags) > 0 {
+				agg.add(line, tags)
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return agg.result(), nil
+}
+
+// NewListTagsTool creates the list_tags tool
+func NewListTagsTool(client *ynab.Client) ToolDefinition {
+	properties := transactionFilterProperties()
+	properties["budget_id"] = map[string]interface{}{
+		"type":        "string",
+		"description": "The ID of the budget",
+	}
+
+	tool := mcp.Tool{
+		Name:        "list_tags",
+		Description: "List the #hashtags used in transaction and split memos (e.g. #vacation2026, #reimbursable) with how often each is used, when it was first and last used, and its totals. Accepts the same filters as search_transactions.",
+		InputSchema: mcp.ToolInputSchema{
+			Type:       "object",
+			Properties: properties,
+			Required:   []string{"budget_id"},
+		},
+	}
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		budgetID, ok := args["budget_id"].(string)
+		if !ok || budgetID == "" {
+			return mcp.NewToolResultError("budget_id is required"), nil
+		}
+
+		filter, err := parseTransactionFilter(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		// Usage counts include every tagged line, transfers too
+		summaries, err := streamTagSummaries(client, budgetID, filter, transferHandlingAll, false, newProgressReporter(ctx, request))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		tags := sortedTagSummaries(summaries, func(summary *tagSummary) float64 {
+			return float64(summary.TransactionCount)
+		})
+
+		if len(tags) == 0 {
+			return mcp.NewToolResultText("No #tags found in transaction memos."), nil
+		}
+
+		var result strings.Builder
+		result.WriteString(fmt.Sprintf("Found %d tag(s):\n\n", len(tags)))
+		for _, tag := range tags {
+			result.WriteString(fmt.Sprintf("#%s - %d transaction(s)\n", tag.Tag, tag.TransactionCount))
+			result.WriteString(fmt.Sprintf("  Outflow: %s | Inflow: %s\n",
+				ynab.FormatCurrency(ynab.FloatToMilliunits(tag.TotalOutflow)), ynab.FormatCurrency(ynab.FloatToMilliunits(tag.TotalInflow))))
+			result.WriteString(fmt.Sprintf("  Used: %s to %s\n\n", tag.FirstUsed, tag.LastUsed))
+		}
+
+		return mcp.NewToolResultText(result.String()), nil
+	}
+
+	return ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// NewGetSpendingByTagTool creates the get_spending_by_tag tool
+func NewGetSpendingByTagTool(client *ynab.Client) ToolDefinition {
+	properties := transactionFilterProperties()
+	properties["budget_id"] = map[string]interface{}{
+		"type":        "string",
+		"description": "The ID of the budget",
+	}
+	properties["tags"] = map[string]interface{}{
+		"type":        "array",
+		"description": "Optional: only report these tags (with or without #). Default: every tag",
+		"items": map[string]interface{}{
+			"type": "string",
+		},
+	}
+	properties["include_categories"] = map[string]interface{}{
+		"type":        "boolean",
+		"description": "Optional: break each tag down by category (default true)",
+	}
+	properties["transfer_handling"] = transferHandlingProperty()
+
+	tool := mcp.Tool{
+		Name:        "get_spending_by_tag",
+		Description: "Total spending by memo #hashtag across categories, for tracking cross-category projects like trips or reimbursable expenses. Tags in a split's own memo apply to that split; tags in the parent memo apply to every split. A transaction with several tags counts toward each, so tag totals are not additive.",
+		InputSchema: mcp.ToolInputSchema{
+			Type:       "object",
+			Properties: properties,
+			Required:   []string{"budget_id"},
+		},
+	}
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		budgetID, ok := args["budget_id"].(string)
+		if !ok || budgetID == "" {
+			return mcp.NewToolResultError("budget_id is required"), nil
+		}
+
+		transferHandling, err := parseTransferHandling(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		filter, err := parseTransactionFilter(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		includeCategories := true
+		if include, ok := args["include_categories"].(bool); ok {
+			includeCategories = include
+		}
+
+		wanted := make(map[string]bool)
+		for _, tag := range stringSliceArg(args, "tags") {
+			if normalized := normalizeTag(tag); normalized != "" {
+				wanted[normalized] = true
+			}
+		}
+
+		summaries, err := streamTagSummaries(client, budgetID, filter, transferHandling, includeCategories, newProgressReporter(ctx, request))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		if len(wanted) > 0 {
+			for tag := range summaries {
+				if !wanted[tag] {
+					delete(summaries, tag)
+				}
+			}
+		}
+
+		tags := sortedTagSummaries(summaries, func(summary *tagSummary) float64 {
+			return summary.TotalOutflow
+		})
+
+		missing := make([]string, 0)
+		for tag := range wanted {
+			if _, found := summaries[tag]; !found {
+				missing = append(missing, tag)
+			}
+		}
+		sort.Strings(missing)
+
+		// Build result
+		result := map[string]interface{}{
+			"tags":  tags,
+			"count": len(tags),
+		}
+		if len(missing) > 0 {
+			result["tags_not_found"] = missing
+		}
+		if filter.SinceDate != "" || filter.UntilDate != "" {
+			result["date_range"] = map[string]string{
+				"since": filter.SinceDate,
+				"until": filter.UntilDate,
+			}
+		}
+
+		jsonResult, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to format result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonResult)), nil
+	}
+
+	return ToolDefinition{Tool: tool, Handler: handler}
+}
@@ -3,6 +3,8 @@ package tools
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,7 +16,7 @@ import (
 func NewListTransactionsTool(client *ynab.Client) ToolDefinition {
 	tool := mcp.Tool{
 		Name:        "list_transactions",
-		Description: "List transactions in a budget. Can filter by date (since_date) or type (uncategorized/unapproved). Returns up to most recent transactions.",
+		Description: "List transactions in a budget. Can filter by date (since_date) or type (uncategorized/unapproved). Results are sorted (newest first by default) and paginated: pass the returned next_cursor to fetch the following page. Totals cover every matching transaction, not just the current page.",
 		InputSchema: mcp.ToolInputSchema{
 			Type: "object",
 			Properties: map[string]interface{}{
@@ -35,6 +37,21 @@ func NewListTransactionsTool(client *ynab.Client) ToolDefinition {
 					"type":        "string",
 					"description": "Only return transactions for this specific account ID. Optional.",
 				},
+				"sort": map[string]interface{}{
+					"type":        "string",
+					"description": "Sort order: 'date_desc' (newest first, default), 'date_asc' (oldest first), 'amount_desc' (largest absolute amount first), or 'amount_asc' (smallest absolute amount first). Optional.",
+					"enum":        []string{sortDateDesc, sortDateAsc, sortAmountDesc, sortAmountAsc},
+				},
+				"limit": map[string]interface{}{
+					"type":        "number",
+					"description": fmt.Sprintf("Maximum number of transactions to return (1-%d, default %d). Optional.", maxTransactionPageSize, defaultTransactionPageSize),
+					"minimum":     1,
+					"maximum":     maxTransactionPageSize,
+				},
+				"cursor": map[string]interface{}{
+					"type":        "string",
+					"description": "Cursor from a previous response's next_cursor to continue listing. Optional.",
+				},
 			},
 			Required: []string{"budget_id"},
 		},
@@ -60,6 +77,31 @@ func NewListTransactionsTool(client *ynab.Client) ToolDefinition {
 			query.Type = txType
 		}
 
+		sortBy := sortDateDesc
+		if sortArg, ok := args["sort"].(string); ok && sortArg != "" {
+			if !isValidTransactionSort(sortArg) {
+				return mcp.NewToolResultError(fmt.Sprintf("Invalid sort: %s", sortArg)), nil
+			}
+			sortBy = sortArg
+		}
+
+		limit := defaultTransactionPageSize
+		if limitFloat, ok := args["limit"].(float64); ok {
+			limit = int(limitFloat)
+			if limit < 1 || limit > maxTransactionPageSize {
+				return mcp.NewToolResultError(fmt.Sprintf("limit must be between 1 and %d", maxTransactionPageSize)), nil
+			}
+		}
+
+		offset := 0
+		if cursor, ok := args["cursor"].(string); ok && cursor != "" {
+			parsed, err := parseCursor(cursor)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			offset = parsed
+		}
+
 		var transactions []ynab.Transaction
 		var err error
 
@@ -74,66 +116,41 @@ func NewListTransactionsTool(client *ynab.Client) ToolDefinition {
 			return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch transactions: %v", err)), nil
 		}
 
-		if len(transactions) == 0 {
-			return mcp.NewToolResultText("No transactions found."), nil
-		}
-
-		var result strings.Builder
-		result.WriteString(fmt.Sprintf("Found %d transaction(s):\n\n", len(transactions)))
-
-		// Limit display to most recent 50 transactions
-		displayCount := len(transactions)
-		if displayCount > 50 {
-			displayCount = 50
-		}
-
+		// Drop deleted transactions up front so numbering and totals only
+		// reflect what the user actually sees in YNAB
+		active := make([]ynab.Transaction, 0, len(transactions))
 		totalAmount := int64(0)
-		for i := 0; i < displayCount; i++ {
-			tx := transactions[i]
+		for _, tx := range transactions {
 			if tx.Deleted {
 				continue
 			}
-
+			active = append(active, tx)
 			totalAmount += tx.Amount
+		}
 
-			// Format cleared status
-			clearedSymbol := "⚪" // uncleared
-			if tx.Cleared == "cleared" {
-				clearedSymbol = "✓"
-			} else if tx.Cleared == "reconciled" {
-				clearedSymbol = "🔒"
-			}
-
-			// Format approval status
-			approvalSymbol := ""
-			if !tx.Approved {
-				approvalSymbol = " [UNAPPROVED]"
-			}
+		if len(active) == 0 {
+			return mcp.NewToolResultText("No transactions found."), nil
+		}
 
-			result.WriteString(fmt.Sprintf("%d. %s %s - %s%s\n",
-				i+1,
-				tx.Date,
-				clearedSymbol,
-				tx.PayeeName,
-				approvalSymbol))
-			result.WriteString(fmt.Sprintf("   ID: %s\n", tx.ID))
-			result.WriteString(fmt.Sprintf("   Amount: %s\n", ynab.FormatCurrency(tx.Amount)))
-			result.WriteString(fmt.Sprintf("   Account: %s\n", tx.AccountName))
-			if tx.CategoryName != "" {
-				result.WriteString(fmt.Sprintf("   Category: %s\n", tx.CategoryName))
-			}
-			if tx.Memo != "" {
-				result.WriteString(fmt.Sprintf("   Memo: %s\n", tx.Memo))
-			}
-			result.WriteString("\n")
+		if offset >= len(active) {
+			return mcp.NewToolResultError(fmt.Sprintf("cursor is past the end of the results (%d transactions)", len(active))), nil
 		}
 
-		if len(transactions) > displayCount {
-			result.WriteString(fmt.Sprintf("... and %d more transactions (showing most recent %d)\n\n",
-				len(transactions)-displayCount, displayCount))
+		sortTransactions(active, sortBy)
+		page, nextCursor := paginate(len(active), offset, limit)
+
+		var result strings.Builder
+		result.WriteString(fmt.Sprintf("Found %d transaction(s), showing %d-%d (sorted by %s):\n\n",
+			len(active), page.start+1, page.end, describeTransactionSort(sortBy)))
+
+		for i := page.start; i < page.end; i++ {
+			writeTransactionEntry(&result, i+1, active[i])
 		}
 
-		result.WriteString(fmt.Sprintf("Total Amount (displayed): %s\n", ynab.FormatCurrency(totalAmount)))
+		result.WriteString(fmt.Sprintf("Total Amount (all %d matching): %s\n", len(active), ynab.FormatCurrency(totalAmount)))
+		if nextCursor != "" {
+			result.WriteString(fmt.Sprintf("next_cursor: %s (%d more transaction(s))\n", nextCursor, len(active)-page.end))
+		}
 
 		return mcp.NewToolResultText(result.String()), nil
 	}
@@ -460,3 +477,141 @@ func NewUpdateTransactionTool(client *ynab.Client) ToolDefinition {
 
 	return ToolDefinition{Tool: tool, Handler: handler}
 }
+
+// Transaction sort orders accepted by the listing tools
+const (
+	sortDateDesc   = "date_desc"
+	sortDateAsc    = "date_asc"
+	sortAmountDesc = "amount_desc"
+	sortAmountAsc  = "amount_asc"
+)
+
+const (
+	defaultTransactionPageSize = 50
+	maxTransactionPageSize     = 500
+)
+
+// isValidTransactionSort reports whether sortBy is a supported sort order
+func isValidTransactionSort(sortBy string) bool {
+	switch sortBy {
+	case sortDateDesc, sortDateAsc, sortAmountDesc, sortAmountAsc:
+		return true
+	}
+	return false
+}
+
+// describeTransactionSort returns a human readable label for a sort order
+func describeTransactionSort(sortBy string) string {
+	switch sortBy {
+	case sortDateAsc:
+		return "date, oldest first"
+	case sortAmountDesc:
+		return "amount, largest first"
+	case sortAmountAsc:
+		return "amount, smallest first"
+	default:
+		return "date, newest first"
+	}
+}
+
+// sortTransactions sorts transactions in place. Amount sorts compare absolute
+// values so large inflows and outflows rank together. Ties fall back to date
+// (newest first) and then ID so pagination stays stable between calls.
+func sortTransactions(transactions []ynab.Transaction, sortBy string) {
+	sort.SliceStable(transactions, func(i, j int) bool {
+		a, b := transactions[i], transactions[j]
+		switch sortBy {
+		case sortDateAsc:
+			if a.Date != b.Date {
+				return a.Date < b.Date
+			}
+		case sortAmountDesc, sortAmountAsc:
+			absA, absB := absMilliunits(a.Amount), absMilliunits(b.Amount)
+			if absA != absB {
+				if sortBy == sortAmountDesc {
+					return absA > absB
+				}
+				return absA < absB
+			}
+			if a.Date != b.Date {
+				return a.Date > b.Date
+			}
+		default:
+			if a.Date != b.Date {
+				return a.Date > b.Date
+			}
+		}
+		return a.ID < b.ID
+	})
+}
+
+// absMilliunits returns the absolute value of a milliunit amount
+func absMilliunits(amount int64) int64 {
+	if amount < 0 {
+		return -amount
+	}
+	return amount
+}
+
+// pageBounds holds the half-open [start, end) range of a result page
+type pageBounds struct {
+	start int
+	end   int
+}
+
+// paginate computes the page range for a result set and the cursor for the
+// following page (empty when there are no more results)
+func paginate(total, offset, limit int) (pageBounds, string) {
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+	nextCursor := ""
+	if end < total {
+		nextCursor = strconv.Itoa(end)
+	}
+	return pageBounds{start: offset, end: end}, nextCursor
+}
+
+// parseCursor decodes a pagination cursor into a result offset
+func parseCursor(cursor string) (int, error) {
+	offset, err := strconv.Atoi(cursor)
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("invalid cursor: %s", cursor)
+	}
+	return offset, nil
+}
+
+// writeTransactionEntry writes a numbered, human readable transaction entry
+func writeTransactionEntry(result *strings.Builder, number int, tx ynab.Transaction) {
+	// Format cleared status
+	clearedSymbol := "⚪" // uncleared
+	if tx.Cleared == "cleared" {
+		clearedSymbol = "✓"
+	} else if tx.Cleared == "reconciled" {
+		clearedSymbol = "🔒"
+	}
+
+	// Format approval status
+	approvalSymbol := ""
+	if !tx.Approved {
+		approvalSymbol = " [UNAPPROVED]"
+	}
+
+	result.WriteString(fmt.Sprintf("%d. %s %s - %s%s\n",
+		number,
+		tx.Date,
+		clearedSymbol,
+		tx.PayeeName,
+		approvalSymbol))
+	result.WriteString(fmt.Sprintf("   ID: %s\n", tx.ID))
+	result.WriteString(fmt.Sprintf("   Amount: %s\n", ynab.FormatCurrency(tx.Amount)))
+	result.WriteString(fmt.Sprintf("   Account: %s\n", tx.AccountName))
+	if tx.CategoryName != "" {
+		result.WriteString(fmt.Sprintf("   Category: %s\n", tx.CategoryName))
+	}
+	if tx.Memo != "" {
+		result.WriteString(fmt.Sprintf("   Memo: %s\n", tx.Memo))
+	}
+	result.WriteString("\n")
+}
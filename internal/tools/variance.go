@@ -0,0 +1,306 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/jeff-french/ynab-mcp-server/internal/ynab"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	defaultVarianceMonths = 6
+	defaultVarianceTopN   = 10
+
+	// YNAB's built-in groups hold Ready to Assign and credit card payment
+	// categories, whose activity is not spending against a plan
+	internalCategoryGroupName       = "Internal Master Category"
+	creditCardPaymentsCategoryGroup = "Credit Card Payments"
+)
+
+// isBudgetCategoryGroup reports whether a group holds regular spending categories
+func isBudgetCategoryGroup(group ynab.CategoryGroup) bool {
+	return !group.Deleted && group.Name != internalCategoryGroupName && group.Name != creditCardPaymentsCategoryGroup
+}
+
+// categoryMonthVariance is one category's assigned vs actual for one month
+type categoryMonthVariance struct {
+	CategoryID        string  `json:"category_id"`
+	CategoryName      string  `json:"category_name"`
+	CategoryGroupName string  `json:"category_group_name"`
+	Assigned          float64 `json:"assigned"`
+	Spent             float64 `json:"spent"`
+	Variance          float64 `json:"variance"`
+}
+
+// monthVariance summarizes assigned vs actual across categories for a month
+type monthVariance struct {
+	Month         string                  `json:"month"`
+	TotalAssigned float64                 `json:"total_assigned"`
+	TotalSpent    float64                 `json:"total_spent"`
+	Overspent     float64                 `json:"overspent"`
+	Underspent    float64                 `json:"underspent"`
+	NetVariance   float64                 `json:"net_variance"`
+	Overspending  []categoryMonthVariance `json:"overspending_categories"`
+}
+
+// categoryVariance accumulates a category's variance across months
+type categoryVariance struct {
+	CategoryID         string  `json:"category_id"`
+	CategoryName       string  `json:"category_name"`
+	CategoryGroupName  string  `json:"category_group_name"`
+	TotalAssigned      float64 `json:"total_assigned"`
+	TotalSpent         float64 `json:"total_spent"`
+	TotalVariance      float64 `json:"total_variance"`
+	TotalOverspent     float64 `json:"total_overspent"`
+	MonthsOverspent    int     `json:"months_overspent"`
+	MonthsUnderspent   int     `json:"months_underspent"`
+	AverageAbsVariance float64 `json:"average_abs_variance"`
+	Pattern            string  `json:"pattern"`
+
+	months       int
+	absVariances int64
+}
+
+// variancePattern describes whether a category consistently misses its plan
+func variancePattern(c *categoryVariance) string {
+	switch {
+	case c.months == 0:
+		return "on_plan"
+	case float64(c.MonthsOverspent)/float64(c.months) >= 0.5:
+		return "chronic_overspend"
+	case float64(c.MonthsUnderspent)/float64(c.months) >= 0.5:
+		return "chronic_underspend"
+	case c.MonthsOverspent+c.MonthsUnderspent == 0:
+		return "on_plan"
+	default:
+		return "mixed"
+	}
+}
+
+// NewGetBudgetVarianceTool creates the get_budget_variance tool
+func NewGetBudgetVarianceTool(client *ynab.Client) ToolDefinition {
+	tool := mcp.Tool{
+		Name:        "get_budget_variance",
+		Description: "Budget-vs-actual variance report. For each month in a range, compares every category's assigned amount with its spending activity, reports overspending and underspending per month and in total, and ranks categories by how consistently they miss their plan.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"budget_id": map[string]interface{}{
+					"type":        "string",
+					"description": "The ID of the budget",
+				},
+				"since_month": map[string]interface{}{
+					"type":        "string",
+					"description": fmt.Sprintf("Optional: first month in YYYY-MM format (default: %d months ending with until_month)", defaultVarianceMonths),
+				},
+				"until_month": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional: last month in YYYY-MM format (default: current month)",
+				},
+				"top_n": map[string]interface{}{
+					"type":        "number",
+					"description": fmt.Sprintf("Optional: number of categories to rank (default %d)", defaultVarianceTopN),
+				},
+			},
+			Required: []string{"budget_id"},
+		},
+	}
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments"), nil
+		}
+
+		budgetID, ok := args["budget_id"].(string)
+		if !ok || budgetID == "" {
+			return mcp.NewToolResultError("budget_id is required"), nil
+		}
+
+		untilMonth := getCurrentMonth()
+		if monthArg, ok := args["until_month"].(string); ok && monthArg != "" {
+			untilMonth = monthArg
+		}
+		until, err := parseMonth(untilMonth)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid until_month: %v", err)), nil
+		}
+
+		sinceMonth := getMonthString(until.AddDate(0, 1-defaultVarianceMonths, 0))
+		if monthArg, ok := args["since_month"].(string); ok && monthArg != "" {
+			sinceMonth = monthArg
+		}
+
+		months, err := getMonthRange(sinceMonth, untilMonth)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid month range: %v", err)), nil
+		}
+
+		topN := defaultVarianceTopN
+		if topNFloat, ok := args["top_n"].(float64); ok && topNFloat > 0 {
+			topN = int(topNFloat)
+		}
+
+		// Month endpoints don't say which group a category belongs to
+		groups, err := client.ListCategories(budgetID)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch categories: %v", err)), nil
+		}
+		groupNames := make(map[string]string)
+		for _, group := range groups {
+			if !isBudgetCategoryGroup(group) {
+				continue
+			}
+			for _, category := range group.Categories {
+				groupNames[category.ID] = group.Name
+			}
+		}
+
+		byCategory := make(map[string]*categoryVariance)
+		monthly := make([]monthVariance, 0, len(months))
+		totalAssigned := int64(0)
+		totalSpent := int64(0)
+		totalOverspent := int64(0)
+		totalUnderspent := int64(0)
+
+		for _, month := range months {
+			budgetMonth, err := client.GetMonth(budgetID, month+"-01")
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch month %s: %v", month, err)), nil
+			}
+
+			summary := monthVariance{Month: month, Overspending: []categoryMonthVariance{}}
+			assigned := int64(0)
+			spent := int64(0)
+			overspent := int64(0)
+			underspent := int64(0)
+
+			for _, category := range budgetMonth.Categories {
+				groupName, tracked := groupNames[category.ID]
+				if !tracked || category.Deleted {
+					continue
+				}
+				if category.Budgeted == 0 && category.Activity == 0 {
+					continue
+				}
+
+				// Activity is negative for spending, so assigned + activity is what's left over
+				variance := category.Budgeted + category.Activity
+				assigned += category.Budgeted
+				spent -= category.Activity
+
+				acc, ok := byCategory[category.ID]
+				if !ok {
+					acc = &categoryVariance{
+						CategoryID:        category.ID,
+						CategoryName:      category.Name,
+						CategoryGroupName: groupName,
+					}
+					byCategory[category.ID] = acc
+				}
+				acc.months++
+				acc.TotalAssigned += ynab.MilliunitsToFloat(category.Budgeted)
+				acc.TotalSpent += ynab.MilliunitsToFloat(-category.Activity)
+				acc.TotalVariance += ynab.MilliunitsToFloat(variance)
+				acc.absVariances += absMilliunits(variance)
+
+				switch {
+				case variance < 0:
+					overspent += -variance
+					acc.MonthsOverspent++
+					acc.TotalOverspent += ynab.MilliunitsToFloat(-variance)
+					summary.Overspending = append(summary.Overspending, categoryMonthVariance{
+						CategoryID:        category.ID,
+						CategoryName:      category.Name,
+						CategoryGroupName: groupName,
+						Assigned:          ynab.MilliunitsToFloat(category.Budgeted),
+						Spent:             ynab.MilliunitsToFloat(-category.Activity),
+						Variance:          ynab.MilliunitsToFloat(variance),
+					})
+				case variance > 0:
+					underspent += variance
+					acc.MonthsUnderspent++
+				}
+			}
+
+			sort.Slice(summary.Overspending, func(i, j int) bool {
+				return summary.Overspending[i].Variance < summary.Overspending[j].Variance
+			})
+
+			summary.TotalAssigned = ynab.MilliunitsToFloat(assigned)
+			summary.TotalSpent = ynab.MilliunitsToFloat(spent)
+			summary.Overspent = ynab.MilliunitsToFloat(overspent)
+			summary.Underspent = ynab.MilliunitsToFloat(underspent)
+			summary.NetVariance = ynab.MilliunitsToFloat(assigned - spent)
+			monthly = append(monthly, summary)
+
+			totalAssigned += assigned
+			totalSpent += spent
+			totalOverspent += overspent
+			totalUnderspent += underspent
+		}
+
+		categories := make([]*categoryVariance, 0, len(byCategory))
+		for _, acc := range byCategory {
+			acc.TotalAssigned = roundTo(acc.TotalAssigned, 2)
+			acc.TotalSpent = roundTo(acc.TotalSpent, 2)
+			acc.TotalVariance = roundTo(acc.TotalVariance, 2)
+			acc.TotalOverspent = roundTo(acc.TotalOverspent, 2)
+			acc.AverageAbsVariance = roundTo(ynab.MilliunitsToFloat(acc.absVariances)/float64(acc.months), 2)
+			acc.Pattern = variancePattern(acc)
+			categories = append(categories, acc)
+		}
+
+		// Rank by how often a category misses its plan, then by how far
+		sort.Slice(categories, func(i, j int) bool {
+			missI := math.Max(float64(categories[i].MonthsOverspent), float64(categories[i].MonthsUnderspent))
+			missJ := math.Max(float64(categories[j].MonthsOverspent), float64(categories[j].MonthsUnderspent))
+			if missI != missJ {
+				return missI > missJ
+			}
+			return categories[i].AverageAbsVariance > categories[j].AverageAbsVariance
+		})
+
+		chronicOverspenders := make([]string, 0)
+		for _, acc := range categories {
+			if acc.Pattern == "chronic_overspend" {
+				chronicOverspenders = append(chronicOverspenders, acc.CategoryName)
+			}
+		}
+
+		if len(categories) > topN {
+			categories = categories[:topN]
+		}
+
+		// Build result
+		result := map[string]interface{}{
+			"months": monthly,
+			"totals": map[string]float64{
+				"assigned":     ynab.MilliunitsToFloat(totalAssigned),
+				"spent":        ynab.MilliunitsToFloat(totalSpent),
+				"overspent":    ynab.MilliunitsToFloat(totalOverspent),
+				"underspent":   ynab.MilliunitsToFloat(totalUnderspent),
+				"net_variance": ynab.MilliunitsToFloat(totalAssigned - totalSpent),
+			},
+			"ranked_categories":    categories,
+			"chronic_overspenders": chronicOverspenders,
+			"period": map[string]string{
+				"since_month": months[0],
+				"until_month": months[len(months)-1],
+			},
+		}
+
+		jsonResult, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to format result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonResult)), nil
+	}
+
+	return ToolDefinition{Tool: tool, Handler: handler}
+}
@@ -33,14 +33,32 @@ func (c *Client) GetCategory(budgetID, categoryID string) (*Category, error) {
 
 // GetCategoryByMonth returns category details for a specific month
 func (c *Client) GetCategoryByMonth(budgetID, month, categoryID string) (*Category, error) {
-	var resp struct {
-		Data struct {
-			Category Category `json:"category"`
-		} `json:"data"`
-	}
+	var resp CategoryResponse
 	path := fmt.Sprintf("/budgets/%s/months/%s/categories/%s", budgetID, month, categoryID)
 	if err := c.get(path, &resp); err != nil {
 		return nil, err
 	}
 	return &resp.Data.Category, nil
 }
+
+// SaveMonthCategoryRequest represents a request to update a category's
+// assigned amount for a month
+type SaveMonthCategoryRequest struct {
+	Category struct {
+		Budgeted int64 `json:"budgeted"` // in milliunits
+	} `json:"category"`
+}
+
+// UpdateMonthCategory sets the amount assigned to a category for a month.
+// Month is an ISO date for the first of the month (YYYY-MM-01) or "current".
+func (c *Client) UpdateMonthCategory(budgetID, month, categoryID string, budgeted int64) (*Category, error) {
+	req := &SaveMonthCategoryRequest{}
+	req.Category.Budgeted = budgeted
+
+	var resp CategoryResponse
+	path := fmt.Sprintf("/budgets/%s/months/%s/categories/%s", budgetID, month, categoryID)
+	if err := c.patch(path, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Data.Category, nil
+}
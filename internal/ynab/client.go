@@ -3,6 +3,7 @@ package ynab
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -13,6 +14,7 @@ import (
 const (
 	baseURL        = "https://api.ynab.com/v1"
 	requestTimeout = 30 * time.Second
+	streamTimeout  = 10 * time.Minute
 	maxRetries     = 3
 )
 
@@ -44,29 +46,10 @@ func (c *Client) doRequest(method, path string, body interface{}, result interfa
 			time.Sleep(backoff)
 		}
 
-		// Prepare request body
-		var bodyReader io.Reader
-		if body != nil {
-			jsonBody, err := json.Marshal(body)
-			if err != nil {
-				return fmt.Errorf("failed to marshal request body: %w", err)
-			}
-			bodyReader = bytes.NewReader(jsonBody)
-		}
-
-		// Create HTTP request
-		url := baseURL + path
-		req, err := http.NewRequest(method, url, bodyReader)
+		req, err := c.newRequest(method, path, body)
 		if err != nil {
-			return fmt.Errorf("failed to create request: %w", err)
-		}
-
-		// Add headers
-		req.Header.Set("Authorization", "Bearer "+c.accessToken)
-		if body != nil {
-			req.Header.Set("Content-Type", "application/json")
+			return err
 		}
-		req.Header.Set("Accept", "application/json")
 
 		// Execute request
 		resp, err := c.httpClient.Do(req)
@@ -94,11 +77,7 @@ func (c *Client) doRequest(method, path string, body interface{}, result interfa
 
 		// Handle other HTTP errors
 		if resp.StatusCode >= 400 {
-			var apiErr APIErrorResponse
-			if err := json.Unmarshal(respBody, &apiErr); err == nil && apiErr.Error.Detail != "" {
-				return fmt.Errorf("YNAB API error (%d): %s", resp.StatusCode, apiErr.Error.Detail)
-			}
-			return fmt.Errorf("YNAB API error: status %d", resp.StatusCode)
+			return apiError(resp.StatusCode, respBody)
 		}
 
 		// Parse successful response
@@ -114,6 +93,112 @@ func (c *Client) doRequest(method, path string, body interface{}, result interfa
 	return fmt.Errorf("request failed after %d attempts: %w", maxRetries, lastErr)
 }
 
+// newRequest builds an authenticated API request with a JSON body
+func (c *Client) newRequest(method, path string, body interface{}) (*http.Request, error) {
+	// Prepare request body
+	var bodyReader io.Reader
+	if body != nil {
+		jsonBody, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		bodyReader = bytes.NewReader(jsonBody)
+	}
+
+	// Create HTTP request
+	url := baseURL + path
+	req, err := http.NewRequest(method, url, bodyReader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Add headers
+	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	return req, nil
+}
+
+// APIError is an error response from the YNAB API
+type APIError struct {
+	StatusCode int
+	Detail     string
+}
+
+func (e *APIError) Error() string {
+	if e.Detail != "" {
+		return fmt.Sprintf("YNAB API error (%d): %s", e.StatusCode, e.Detail)
+	}
+	return fmt.Sprintf("YNAB API error: status %d", e.StatusCode)
+}
+
+// IsNotFound reports whether err is a YNAB "not found" response
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// apiError converts an error response into an error, using YNAB's detail message when present
+func apiError(statusCode int, body []byte) error {
+	var apiErr APIErrorResponse
+	if err := json.Unmarshal(body, &apiErr); err == nil && apiErr.Error.Detail != "" {
+		return &APIError{StatusCode: statusCode, Detail: apiErr.Error.Detail}
+	}
+	return &APIError{StatusCode: statusCode}
+}
+
+// stream performs a GET request and hands the response body to decode as it
+// arrives instead of buffering it, for responses too large to hold in memory.
+// Connection failures and rate limits are retried like doRequest, but once
+// decoding has started a failure is returned as is.
+func (c *Client) stream(path string, decode func(body io.Reader) error) error {
+	// Large responses can take longer to read than a normal request allows
+	httpClient := *c.httpClient
+	httpClient.Timeout = streamTimeout
+
+	var lastErr error
+	for attempt := 0; attempt < maxRetries; attempt++ {
+		if attempt > 0 {
+			backoff := time.Duration(1<<uint(attempt-1)) * time.Second
+			slog.Debug("Retrying request after backoff", "attempt", attempt, "backoff", backoff)
+			time.Sleep(backoff)
+		}
+
+		req, err := c.newRequest("GET", path, nil)
+		if err != nil {
+			return err
+		}
+
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			lastErr = fmt.Errorf("request failed: %w", err)
+			slog.Warn("HTTP request failed", "error", err, "attempt", attempt+1)
+			continue
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests {
+			resp.Body.Close()
+			lastErr = fmt.Errorf("rate limit exceeded")
+			slog.Warn("Rate limit exceeded, will retry", "attempt", attempt+1)
+			continue
+		}
+
+		if resp.StatusCode >= 400 {
+			respBody, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return apiError(resp.StatusCode, respBody)
+		}
+
+		err = decode(resp.Body)
+		resp.Body.Close()
+		return err
+	}
+
+	return fmt.Errorf("request failed after %d attempts: %w", maxRetries, lastErr)
+}
+
 // get performs a GET request
 func (c *Client) get(path string, result interface{}) error {
 	return c.doRequest("GET", path, nil, result)
@@ -128,3 +213,13 @@ func (c *Client) post(path string, body interface{}, result interface{}) error {
 func (c *Client) put(path string, body interface{}, result interface{}) error {
 	return c.doRequest("PUT", path, body, result)
 }
+
+// patch performs a PATCH request
+func (c *Client) patch(path string, body interface{}, result interface{}) error {
+	return c.doRequest("PATCH", path, body, result)
+}
+
+// delete performs a DELETE request
+func (c *Client) delete(path string, result interface{}) error {
+	return c.doRequest("DELETE", path, nil, result)
+}
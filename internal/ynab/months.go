@@ -0,0 +1,14 @@
+package ynab
+
+import "fmt"
+
+// GetMonth returns a budget month with its category data. Month is either
+// an ISO date for the first of the month (YYYY-MM-01) or "current".
+func (c *Client) GetMonth(budgetID, month string) (*Month, error) {
+	var resp MonthDetailResponse
+	path := fmt.Sprintf("/budgets/%s/months/%s", budgetID, month)
+	if err := c.get(path, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Data.Month, nil
+}
@@ -0,0 +1,13 @@
+package ynab
+
+import "fmt"
+
+// ListScheduledTransactions returns all scheduled transactions for a budget
+func (c *Client) ListScheduledTransactions(budgetID string) ([]ScheduledTransaction, error) {
+	var resp ScheduledTransactionsResponse
+	path := fmt.Sprintf("/budgets/%s/scheduled_transactions", budgetID)
+	if err := c.get(path, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data.ScheduledTransactions, nil
+}
@@ -1,7 +1,9 @@
 package ynab
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/url"
 )
 
@@ -11,23 +13,27 @@ type TransactionQuery struct {
 	Type      string // uncategorized, unapproved
 }
 
+// withTransactionQuery appends the query parameters, if any, to a transactions path
+func withTransactionQuery(path string, query *TransactionQuery) string {
+	if query == nil {
+		return path
+	}
+	params := url.Values{}
+	if query.SinceDate != "" {
+		params.Add("since_date", query.SinceDate)
+	}
+	if query.Type != "" {
+		params.Add("type", query.Type)
+	}
+	if len(params) > 0 {
+		path += "?" + params.Encode()
+	}
+	return path
+}
+
 // ListTransactions returns all transactions for a budget
 func (c *Client) ListTransactions(budgetID string, query *TransactionQuery) ([]Transaction, error) {
-	path := fmt.Sprintf("/budgets/%s/transactions", budgetID)
-
-	// Add query parameters if provided
-	if query != nil {
-		params := url.Values{}
-		if query.SinceDate != "" {
-			params.Add("since_date", query.SinceDate)
-		}
-		if query.Type != "" {
-			params.Add("type", query.Type)
-		}
-		if len(params) > 0 {
-			path += "?" + params.Encode()
-		}
-	}
+	path := withTransactionQuery(fmt.Sprintf("/budgets/%s/transactions", budgetID), query)
 
 	var resp TransactionsResponse
 	if err := c.get(path, &resp); err != nil {
@@ -98,27 +104,147 @@ func (c *Client) UpdateTransaction(budgetID, transactionID string, req *UpdateTr
 	return &resp.Data.Transaction, nil
 }
 
+// DeleteTransaction deletes an existing transaction
+func (c *Client) DeleteTransaction(budgetID, transactionID string) (*Transaction, error) {
+	var resp TransactionResponse
+	path := fmt.Sprintf("/budgets/%s/transactions/%s", budgetID, transactionID)
+	if err := c.delete(path, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Data.Transaction, nil
+}
+
+// BulkTransactionUpdate represents the changes to apply to one transaction in
+// a bulk update. Only non-empty fields are sent to YNAB.
+type BulkTransactionUpdate struct {
+	ID         string `json:"id"`
+	PayeeName  string `json:"payee_name,omitempty"`
+	CategoryID string `json:"category_id,omitempty"`
+	Memo       string `json:"memo,omitempty"`
+	Cleared    string `json:"cleared,omitempty"`
+	Approved   *bool  `json:"approved,omitempty"`
+	FlagColor  string `json:"flag_color,omitempty"`
+}
+
+// UpdateTransactionsRequest represents a request to update multiple transactions
+type UpdateTransactionsRequest struct {
+	Transactions []BulkTransactionUpdate `json:"transactions"`
+}
+
+// UpdateTransactions updates multiple transactions in a single request
+func (c *Client) UpdateTransactions(budgetID string, req *UpdateTransactionsRequest) ([]Transaction, error) {
+	var resp TransactionsResponse
+	path := fmt.Sprintf("/budgets/%s/transactions", budgetID)
+	if err := c.patch(path, req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data.Transactions, nil
+}
+
 // ListAccountTransactions returns all transactions for a specific account
 func (c *Client) ListAccountTransactions(budgetID, accountID string, query *TransactionQuery) ([]Transaction, error) {
-	path := fmt.Sprintf("/budgets/%s/accounts/%s/transactions", budgetID, accountID)
+	path := withTransactionQuery(fmt.Sprintf("/budgets/%s/accounts/%s/transactions", budgetID, accountID), query)
+
+	var resp TransactionsResponse
+	if err := c.get(path, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data.Transactions, nil
+}
+
+// StreamTransactions calls fn with each transaction for a budget as it is
+// decoded from the response, so memory use doesn't grow with the number of
+// transactions. An error from fn stops the stream and is returned.
+func (c *Client) StreamTransactions(budgetID string, query *TransactionQuery, fn func(Transaction) error) error {
+	path := withTransactionQuery(fmt.Sprintf("/budgets/%s/transactions", budgetID), query)
+	return c.stream(path, func(body io.Reader) error {
+		return decodeTransactions(body, fn)
+	})
+}
+
+// StreamAccountTransactions is StreamTransactions for a single account
+func (c *Client) StreamAccountTransactions(budgetID, accountID string, query *TransactionQuery, fn func(Transaction) error) error {
+	path := withTransactionQuery(fmt.Sprintf("/budgets/%s/accounts/%s/transactions", budgetID, accountID), query)
+	return c.stream(path, func(body io.Reader) error {
+		return decodeTransactions(body, fn)
+	})
+}
+
+// decodeTransactions walks a transactions response
+// ({"data": {"transactions": [...], ...}}) token by token, decoding one
+// transaction at a time and skipping every other value
+func decodeTransactions(r io.Reader, fn func(Transaction) error) error {
+	dec := json.NewDecoder(r)
 
-	// Add query parameters if provided
-	if query != nil {
-		params := url.Values{}
-		if query.SinceDate != "" {
-			params.Add("since_date", query.SinceDate)
+	parseErr := func(err error) error {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	// eachKey calls visit for every key of the object that starts at the next token
+	eachKey := func(visit func(key string) error) error {
+		if err := expectDelim(dec, '{'); err != nil {
+			return parseErr(err)
 		}
-		if query.Type != "" {
-			params.Add("type", query.Type)
+		for dec.More() {
+			token, err := dec.Token()
+			if err != nil {
+				return parseErr(err)
+			}
+			key, _ := token.(string)
+			if err := visit(key); err != nil {
+				return err
+			}
 		}
-		if len(params) > 0 {
-			path += "?" + params.Encode()
+		if err := expectDelim(dec, '}'); err != nil {
+			return parseErr(err)
 		}
+		return nil
 	}
 
-	var resp TransactionsResponse
-	if err := c.get(path, &resp); err != nil {
-		return nil, err
+	skip := func() error {
+		var discard json.RawMessage
+		if err := dec.Decode(&discard); err != nil {
+			return parseErr(err)
+		}
+		return nil
 	}
-	return resp.Data.Transactions, nil
+
+	return eachKey(func(key string) error {
+		if key != "data" {
+			return skip()
+		}
+		return eachKey(func(key string) error {
+			if key != "transactions" {
+				return skip()
+			}
+			if err := expectDelim(dec, '['); err != nil {
+				return parseErr(err)
+			}
+			for dec.More() {
+				var tx Transaction
+				if err := dec.Decode(&tx); err != nil {
+					return parseErr(err)
+				}
+				if err := fn(tx); err != nil {
+					return err
+				}
+			}
+			if err := expectDelim(dec, ']'); err != nil {
+				return parseErr(err)
+			}
+			return nil
+		})
+	})
+}
+
+// expectDelim reads the next token and checks that it is the given delimiter
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("expected %q, got %v", delim, token)
+	}
+	return nil
 }
@@ -61,6 +61,13 @@ type Account struct {
 	DirectImportLinked  bool   `json:"direct_import_linked"`
 	DirectImportInError bool   `json:"direct_import_in_error"`
 	Deleted             bool   `json:"deleted"`
+
+	// Debt details for loan accounts, keyed by the date each value took effect.
+	// Interest rates are in milli-percent (5.25% is 5250).
+	DebtOriginalBalance *int64           `json:"debt_original_balance"`
+	DebtInterestRates   map[string]int64 `json:"debt_interest_rates,omitempty"`
+	DebtMinimumPayments map[string]int64 `json:"debt_minimum_payments,omitempty"`
+	DebtEscrowAmounts   map[string]int64 `json:"debt_escrow_amounts,omitempty"`
 }
 
 // Transaction represents a YNAB transaction
@@ -232,6 +239,14 @@ type CategoriesResponse struct {
 	} `json:"data"`
 }
 
+// CategoryResponse wraps single category response
+type CategoryResponse struct {
+	Data struct {
+		Category        Category `json:"category"`
+		ServerKnowledge int64    `json:"server_knowledge"`
+	} `json:"data"`
+}
+
 // PayeesResponse wraps payees list response
 type PayeesResponse struct {
 	Data struct {
@@ -240,6 +255,22 @@ type PayeesResponse struct {
 	} `json:"data"`
 }
 
+// MonthDetailResponse wraps single budget month response
+type MonthDetailResponse struct {
+	Data struct {
+		Month           Month `json:"month"`
+		ServerKnowledge int64 `json:"server_knowledge"`
+	} `json:"data"`
+}
+
+// ScheduledTransactionsResponse wraps scheduled transactions list response
+type ScheduledTransactionsResponse struct {
+	Data struct {
+		ScheduledTransactions []ScheduledTransaction `json:"scheduled_transactions"`
+		ServerKnowledge       int64                  `json:"server_knowledge"`
+	} `json:"data"`
+}
+
 // Helper functions
 
 // MilliunitsToFloat converts YNAB milliunits (1/1000 of currency unit) to float